@@ -1,11 +1,15 @@
 package cfn
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/external"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/blueseph/cirrus/colors"
@@ -15,6 +19,9 @@ import (
 var (
 	cfnClient *cloudformation.Client
 
+	//ErrNoChanges is returned when a change set fails to create because the template and parameters match the deployed stack
+	ErrNoChanges = errors.New("the submitted information didn't contain changes")
+
 	//ChangeSetASCII is a map to convert a change action to a glyph representing the action. + for Add, - for Remove, ↻ for Modify
 	ChangeSetASCII map[cloudformation.ChangeAction]string = map[cloudformation.ChangeAction]string{
 		cloudformation.ChangeActionAdd:    "+",
@@ -23,12 +30,22 @@ var (
 	}
 )
 
+//nestedChangeSetsResult captures the change set IDs of nested stack changes, which the SDK's ResourceChange doesn't model
+type nestedChangeSetsResult struct {
+	Changes []struct {
+		LogicalResourceID string `xml:"ResourceChange>LogicalResourceId"`
+		ChangeSetID       string `xml:"ResourceChange>ChangeSetId"`
+	} `xml:"DescribeChangeSetResult>Changes>member"`
+}
+
 //StackOperation is the cloudFormation type of stack operations
 type StackOperation string
 
 const (
 	stackNotFound   string = "does not exist"
 	unknownEndpoint string = "unknown endpoint, could not resolve endpoint"
+	noChanges       string = "didn't contain changes"
+	noUpdates       string = "No updates are to be performed"
 
 	//StackOperationUpdate is the enum value for Stack Operation of update
 	StackOperationUpdate StackOperation = "update"
@@ -53,21 +70,90 @@ func getClient() *cloudformation.Client {
 	return cfnClient
 }
 
-//CreateChanges creates a change set, waits for it to complete creating, then describes the change set.
-func CreateChanges(info data.StackInfo, template []byte, tags []cloudformation.Tag, parameters []cloudformation.Parameter, exists bool) (*cloudformation.DescribeChangeSetResponse, error) {
+//CreateChanges creates a change set, waits for it to complete creating, then describes the change set and its nested change sets. Nested changes
+//are keyed by the display row key of their nested stack.
+func CreateChanges(info data.StackInfo, template []byte, tags []cloudformation.Tag, parameters []cloudformation.Parameter, exists bool) (*cloudformation.DescribeChangeSetResponse, map[string][]cloudformation.Change, error) {
 	err := createChangeSet(info, template, tags, parameters, exists)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	err = waitForChangeSet(info)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	changes, nestedChangeSetIDs, err := describeChangeSetWithNested(info.StackName, info.ChangeSetName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nestedChanges := make(map[string][]cloudformation.Change)
+
+	err = describeNestedChangeSets("", nestedChangeSetIDs, nestedChanges)
+
+	return changes, nestedChanges, err
+}
+
+func describeNestedChangeSets(parent string, nestedChangeSetIDs map[string]string, nestedChanges map[string][]cloudformation.Change) error {
+	for logicalID, changeSetID := range nestedChangeSetIDs {
+		key := data.NestedRowKey(parent, logicalID)
+
+		changes, childChangeSetIDs, err := describeChangeSetWithNested("", changeSetID)
+		if err != nil {
+			return err
+		}
+
+		nestedChanges[key] = changes.Changes
+
+		err = describeNestedChangeSets(key, childChangeSetIDs, nestedChanges)
+		if err != nil {
+			return err
+		}
 	}
 
-	changes, err := describeChangeSet(info)
+	return nil
+}
+
+//includeNestedStacks adds the IncludeNestedStacks parameter to a CreateChangeSet request, which the SDK's CreateChangeSetInput doesn't model
+func includeNestedStacks(r *aws.Request) {
+	if r.Error != nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		r.Error = err
+		return
+	}
+
+	r.SetBufferBody(append(body, []byte("&IncludeNestedStacks=true")...))
+}
+
+//captureNestedChangeSetIDsFn reads the nested change set IDs out of a DescribeChangeSet response before the SDK unmarshals it
+func captureNestedChangeSetIDsFn(nestedChangeSetIDs map[string]string) func(*aws.Request) {
+	return func(r *aws.Request) {
+		body, err := ioutil.ReadAll(r.HTTPResponse.Body)
+		r.HTTPResponse.Body.Close()
+		if err != nil {
+			r.Error = err
+			return
+		}
+
+		r.HTTPResponse.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-	return changes, err
+		result := nestedChangeSetsResult{}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			r.Error = err
+			return
+		}
+
+		for _, change := range result.Changes {
+			if change.ChangeSetID != "" {
+				nestedChangeSetIDs[change.LogicalResourceID] = change.ChangeSetID
+			}
+		}
+	}
 }
 
 func createChangeSet(info data.StackInfo, template []byte, tags []cloudformation.Tag, parameters []cloudformation.Parameter, exists bool) error {
@@ -96,6 +182,7 @@ func createChangeSet(info data.StackInfo, template []byte, tags []cloudformation
 	}
 
 	req := client.CreateChangeSetRequest(&input)
+	req.Handlers.Build.PushBack(includeNestedStacks)
 
 	_, err := req.Send(context.Background())
 	if err != nil {
@@ -122,6 +209,10 @@ func waitForChangeSet(info data.StackInfo) error {
 		}
 
 		if changeSet.Status == cloudformation.ChangeSetStatusFailed {
+			if strings.Contains(*changeSet.StatusReason, noChanges) || strings.Contains(*changeSet.StatusReason, noUpdates) {
+				return ErrNoChanges
+			}
+
 			return errors.New(*changeSet.StatusReason)
 		}
 		return err
@@ -146,6 +237,22 @@ func ExecuteChangeSet(info data.StackInfo) error {
 	return err
 }
 
+// DeleteChangeSet deletes a change set that won't be executed
+func DeleteChangeSet(info data.StackInfo) error {
+	input := cloudformation.DeleteChangeSetInput{
+		StackName:     &info.StackName,
+		ChangeSetName: &info.ChangeSetName,
+	}
+
+	client := getClient()
+
+	req := client.DeleteChangeSetRequest(&input)
+
+	_, err := req.Send(context.Background())
+
+	return err
+}
+
 func describeChangeSet(info data.StackInfo) (*cloudformation.DescribeChangeSetResponse, error) {
 	input := cloudformation.DescribeChangeSetInput{
 		StackName:     &info.StackName,
@@ -159,6 +266,51 @@ func describeChangeSet(info data.StackInfo) (*cloudformation.DescribeChangeSetRe
 	return req.Send(context.Background())
 }
 
+//describeChangeSetWithNested describes a change set, returning the IDs of its nested change sets keyed by the logical ID of their nested stack. The
+//stack name may be empty when the change set name is an ID. DescribeChangeSet returns changes a page at a time, so every page is read and the
+//changes of the later pages are added to the first
+func describeChangeSetWithNested(stackName string, changeSetName string) (*cloudformation.DescribeChangeSetResponse, map[string]string, error) {
+	input := cloudformation.DescribeChangeSetInput{
+		ChangeSetName: &changeSetName,
+	}
+
+	if stackName != "" {
+		input.StackName = &stackName
+	}
+
+	nestedChangeSetIDs := make(map[string]string)
+
+	client := getClient()
+
+	var changeSet *cloudformation.DescribeChangeSetResponse
+
+	for {
+		req := client.DescribeChangeSetRequest(&input)
+		req.Handlers.Unmarshal.PushFront(captureNestedChangeSetIDsFn(nestedChangeSetIDs))
+
+		page, err := req.Send(context.Background())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if changeSet == nil {
+			changeSet = page
+		} else {
+			changeSet.Changes = append(changeSet.Changes, page.Changes...)
+		}
+
+		if page.NextToken == nil || *page.NextToken == "" {
+			break
+		}
+
+		input.NextToken = page.NextToken
+	}
+
+	changeSet.NextToken = nil
+
+	return changeSet, nestedChangeSetIDs, nil
+}
+
 func getChanges(info data.StackInfo) ([]cloudformation.Change, error) {
 	changeSet, err := describeChangeSet(info)
 
@@ -304,6 +456,77 @@ func GetStackResources(info data.StackInfo) cloudformation.ListStackResourcesPag
 	return resources
 }
 
+// SetStackPolicy replaces the stack policy of a stack
+func SetStackPolicy(info data.StackInfo, policy []byte) error {
+	body := string(policy)
+
+	input := cloudformation.SetStackPolicyInput{
+		StackName:       &info.StackName,
+		StackPolicyBody: &body,
+	}
+
+	client := getClient()
+
+	req := client.SetStackPolicyRequest(&input)
+
+	_, err := req.Send(context.Background())
+
+	return err
+}
+
+// GetStackPolicy gets the stack policy of a stack. Stacks without a policy return an empty policy
+func GetStackPolicy(info data.StackInfo) ([]byte, error) {
+	input := cloudformation.GetStackPolicyInput{
+		StackName: &info.StackName,
+	}
+
+	client := getClient()
+
+	req := client.GetStackPolicyRequest(&input)
+
+	resp, err := req.Send(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(aws.StringValue(resp.StackPolicyBody)), nil
+}
+
+// SetTerminationProtection enables or disables termination protection on a stack
+func SetTerminationProtection(stackName string, enabled bool) error {
+	input := cloudformation.UpdateTerminationProtectionInput{
+		StackName:                   &stackName,
+		EnableTerminationProtection: &enabled,
+	}
+
+	client := getClient()
+
+	req := client.UpdateTerminationProtectionRequest(&input)
+
+	_, err := req.Send(context.Background())
+
+	return err
+}
+
+// GetTemplate gets the deployed template of a stack, after transforms are processed
+func GetTemplate(info data.StackInfo) ([]byte, error) {
+	input := cloudformation.GetTemplateInput{
+		StackName:     &info.StackID,
+		TemplateStage: cloudformation.TemplateStageProcessed,
+	}
+
+	client := getClient()
+
+	req := client.GetTemplateRequest(&input)
+
+	resp, err := req.Send(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(aws.StringValue(resp.TemplateBody)), nil
+}
+
 // VerifyAWSCredentials verifies AWS credentials are properly configured by running a List Stack command and analyzing errors for common issues with credentials
 func VerifyAWSCredentials() error {
 	input := cloudformation.ListStacksInput{}
@@ -0,0 +1,67 @@
+package cfn
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/data"
+)
+
+const notImported string = "is not imported by any stack"
+
+// GetExportDependencies gets the exports of a stack along with the stacks that import each of them
+func GetExportDependencies(info data.StackInfo) ([]data.ExportDependency, error) {
+	stack, err := GetStack(info.StackName)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := make([]data.ExportDependency, 0)
+
+	for _, output := range stack.Stacks[0].Outputs {
+		if output.ExportName == nil {
+			continue
+		}
+
+		importers, err := getImports(*output.ExportName)
+		if err != nil {
+			return nil, err
+		}
+
+		dependencies = append(dependencies, data.ExportDependency{
+			OutputKey:  aws.StringValue(output.OutputKey),
+			ExportName: *output.ExportName,
+			Value:      aws.StringValue(output.OutputValue),
+			Importers:  importers,
+		})
+	}
+
+	return dependencies, nil
+}
+
+// getImports gets the names of the stacks that import an export. Exports nobody imports are reported as an error by CloudFormation
+func getImports(exportName string) ([]string, error) {
+	input := cloudformation.ListImportsInput{
+		ExportName: &exportName,
+	}
+
+	client := getClient()
+
+	req := client.ListImportsRequest(&input)
+
+	paginator := cloudformation.NewListImportsPaginator(req)
+	importers := make([]string, 0)
+
+	for paginator.Next(context.Background()) {
+		importers = append(importers, paginator.CurrentPage().Imports...)
+	}
+
+	err := paginator.Err()
+	if err != nil && !strings.Contains(err.Error(), notImported) {
+		return nil, err
+	}
+
+	return importers, nil
+}
@@ -0,0 +1,88 @@
+package cfn
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/utils"
+)
+
+//TailedEvent is a stack event from the tailed stack or one of its nested stacks. The logical ID of nested stack resources is their display row key
+type TailedEvent struct {
+	cloudformation.StackEvent
+	Parent string
+	Root   bool
+}
+
+//tailedStack is a stack whose events are tailed, along with the key of the display row that represents it in its parent stack
+type tailedStack struct {
+	info   data.StackInfo
+	parent string
+}
+
+//EventTailer polls a stack and its nested stacks for events that happened after the tailer was created
+type EventTailer struct {
+	since        time.Time
+	eventIds     map[string]bool
+	stacks       []tailedStack
+	tailedStacks map[string]bool
+}
+
+//NewEventTailer returns an event tailer for the given stack, starting now
+func NewEventTailer(info data.StackInfo) *EventTailer {
+	return &EventTailer{
+		since:        time.Now(),
+		eventIds:     make(map[string]bool),
+		stacks:       []tailedStack{{info: info}},
+		tailedStacks: map[string]bool{info.StackID: true},
+	}
+}
+
+//Poll returns the events that haven't been seen yet, oldest first. Nested stacks are tailed as soon as an event reveals their stack ID, and their own
+//stack events are skipped since the nested stack resource in the parent already reports them
+func (tailer *EventTailer) Poll() []TailedEvent {
+	tailed := make([]TailedEvent, 0)
+
+	for i := 0; i < len(tailer.stacks); i++ {
+		stack := tailer.stacks[i]
+		paginator := GetStackEvents(stack.info)
+
+		for paginator.Next(context.TODO()) {
+			events := paginator.CurrentPage()
+
+			for _, event := range utils.ReverseEvents(events.StackEvents) {
+				if !event.Timestamp.After(tailer.since) || tailer.eventIds[*event.EventId] {
+					continue
+				}
+
+				tailer.eventIds[*event.EventId] = true
+
+				if data.IsStackEvent(stack.info, event) {
+					if stack.parent == "" {
+						tailed = append(tailed, TailedEvent{StackEvent: event, Root: true})
+					}
+
+					continue
+				}
+
+				key := data.NestedRowKey(stack.parent, *event.LogicalResourceId)
+				event.LogicalResourceId = &key
+
+				tailed = append(tailed, TailedEvent{StackEvent: event, Parent: stack.parent})
+
+				if *event.ResourceType == data.CloudformationStackResource && event.PhysicalResourceId != nil && *event.PhysicalResourceId != "" && !tailer.tailedStacks[*event.PhysicalResourceId] {
+					tailer.tailedStacks[*event.PhysicalResourceId] = true
+
+					tailer.stacks = append(tailer.stacks, tailedStack{
+						info:   data.StackInfo{StackName: *event.PhysicalResourceId, StackID: *event.PhysicalResourceId},
+						parent: key,
+					})
+				}
+			}
+		}
+	}
+
+	return tailed
+}
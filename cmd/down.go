@@ -7,17 +7,24 @@ import (
 	"github.com/blueseph/cirrus/cfn"
 	"github.com/blueseph/cirrus/colors"
 	"github.com/blueseph/cirrus/data"
-	"github.com/blueseph/cirrus/ui"
+	"github.com/blueseph/cirrus/engine"
 	"github.com/urfave/cli/v2"
 )
 
 var downFlags = []cli.Flag{
+	stackFlag,
+	envFlag,
+	stackPatternFlag,
+	stackTemplateFlag,
+	ciFlag,
+	yesFlag,
+	allowDestructiveFlag,
+	statefulTypeFlag,
 	&cli.StringFlag{
-		Name:     "stack",
-		Aliases:  []string{"s"},
-		Usage:    "Specifies stack name",
-		Required: true,
+		Name:  "guard-policy",
+		Usage: "Reads the guard policy from `file` instead of the user config directory",
 	},
+	outputFlag,
 }
 
 // DownCommand returns the CLI construct that destroys a CloudFormation stack and watches events
@@ -29,17 +36,29 @@ var DownCommand = &cli.Command{
 }
 
 func downAction(c *cli.Context) error {
-	err := Down(c.String("stack"))
+	mode, err := getOutputMode(c)
 	if err != nil {
-		fmt.Println(colors.Error("Cirrus encountered a fatal error:"))
 		return err
 	}
 
-	return nil
+	guard, err := getGuard(c)
+	if err != nil {
+		return err
+	}
+
+	stackName, err := getStackName(c)
+	if err != nil {
+		return err
+	}
+
+	err = Down(stackName, mode, guard)
+
+	return handleResult(err, mode)
 }
 
-// Down manages the stack deletion lifecycle
-func Down(stackName string) error {
+// Down manages the stack deletion lifecycle, reading deletion policies from the deployed template. Stacks with termination protection or matching
+// a refusing guard rule aren't deleted. Outside the interactive display the deletion is approved by the guard, or a prompt in text mode.
+func Down(stackName string, mode OutputMode, guard Guard) error {
 	err := cfn.VerifyAWSCredentials()
 	if err != nil {
 		return err
@@ -59,9 +78,30 @@ func Down(stackName string) error {
 		return err
 	}
 
+	details := stack.DescribeStacksOutput.Stacks[0]
+
 	info := data.StackInfo{
 		StackName: stackName,
-		StackID:   *stack.DescribeStacksOutput.Stacks[0].StackId,
+		StackID:   *details.StackId,
+	}
+
+	if details.EnableTerminationProtection != nil && *details.EnableTerminationProtection {
+		return errors.New(colors.Error(fmt.Sprintf("Stack %s has termination protection enabled. Run cirrus unprotect --stack %s to delete it", stackName, stackName)))
+	}
+
+	rule, guarded := guard.Policy.Evaluate(stackName, details.Tags)
+	if guarded && rule.Action == data.GuardActionRefuse {
+		return errors.New(colors.Error(fmt.Sprintf("Stack %s can't be deleted, it matches guard rule %s", stackName, rule)))
+	}
+
+	var guardedBy string
+	if guarded {
+		guardedBy = rule.String()
+	}
+
+	err = checkExports(info, nil)
+	if err != nil {
+		return err
 	}
 
 	paginator := cfn.GetStackResources(info)
@@ -71,10 +111,18 @@ func Down(stackName string) error {
 
 	resources := data.GetResourcesFromPaginator(&paginator)
 
-	err = ui.DisplayDeletes(info, resources)
+	template, err := cfn.GetTemplate(info)
+	if err != nil {
+		return err
+	}
+
+	policies, err := data.GetDeletionPolicies(template)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	ctx, cancel, renderer := newRenderer(mode, guard)
+	defer cancel()
+
+	return engine.RunDelete(ctx, renderer, info, resources, policies, guard.StatefulTypes, guardedBy)
 }
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/template"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// validEnvironment matches environment names, which are part of file names
+	validEnvironment = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+	// validStackName matches the stack names CloudFormation accepts
+	validStackName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]{0,127}$`)
+
+	// invalidStackNameCharacters matches the characters stack names can't have, which are replaced in the values of a stack pattern
+	invalidStackNameCharacters = regexp.MustCompile(`[^A-Za-z0-9-]+`)
+)
+
+var stackFlag = &cli.StringFlag{
+	Name:    "stack",
+	Aliases: []string{"s"},
+	Usage:   "Specifies `stack name`, which is derived from the stack pattern with --env",
+}
+
+var envFlag = &cli.StringFlag{
+	Name:    "env",
+	Aliases: []string{"e"},
+	Usage:   "Uses the stack of `environment`. Deploying layers parameters.<env>.json and tags.<env>.json over the parameters and tags",
+}
+
+var stackPatternFlag = &cli.StringFlag{
+	Name:  "stack-pattern",
+	Value: "{dir}-{env}",
+	Usage: "Derives the stack name with --env and without --stack from `pattern`, with {env}, {dir} and {template} replaced",
+}
+
+// stackTemplateFlag is the template of commands that only use it for the {template} of the stack pattern
+var stackTemplateFlag = &cli.StringFlag{
+	Name:    "template",
+	Aliases: []string{"t"},
+	Value:   "./template.yaml",
+	Usage:   "Fills {template} in the stack pattern with the name of template `file`",
+}
+
+// getStackName returns the stack given as a flag or, when using an environment without one, the stack name derived from the stack pattern
+func getStackName(c *cli.Context) (string, error) {
+	if c.String("stack") != "" {
+		return c.String("stack"), nil
+	}
+
+	environment := c.String("env")
+	if environment == "" {
+		return "", errors.New(colors.Error("Specify a stack with --stack, or an environment with --env to derive the stack name"))
+	}
+
+	directory, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	templateLocation := c.String("template")
+
+	replacer := strings.NewReplacer(
+		"{env}", stackNameValue(environment),
+		"{dir}", stackNameValue(filepath.Base(directory)),
+		"{template}", stackNameValue(strings.TrimSuffix(filepath.Base(templateLocation), filepath.Ext(templateLocation))),
+	)
+
+	name := replacer.Replace(c.String("stack-pattern"))
+	if !validStackName.MatchString(name) {
+		return "", errors.New(colors.Error(fmt.Sprintf("Stack pattern %s gives %s, which isn't a valid stack name. Stack names start with a letter and only have letters, numbers and dashes", c.String("stack-pattern"), name)))
+	}
+
+	return name, nil
+}
+
+// stackNameValue replaces the characters stack names can't have in a value of the stack pattern, such as the underscores of a directory name
+func stackNameValue(value string) string {
+	return strings.Trim(invalidStackNameCharacters.ReplaceAllString(value, "-"), "-")
+}
+
+// getStackFiles reads the tags and parameters. Deploying to an environment layers its overlays over them, one of which has to exist, and returns the
+// effective values along with the files they come from. NoEcho parameters are masked in the effective values
+func getStackFiles(c *cli.Context, contents []byte) ([]cloudformation.Tag, []cloudformation.Parameter, *data.Environment, error) {
+	tagsLocation := stackFileLocation(c, "tags")
+	parametersLocation := stackFileLocation(c, "parameters")
+
+	environment := c.String("env")
+	if environment == "" {
+		tags, err := data.GetTags(tagsLocation)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		parameters, err := data.GetParameters(parametersLocation)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return tags, parameters, nil, nil
+	}
+
+	if !validEnvironment.MatchString(environment) {
+		return nil, nil, nil, errors.New(colors.Error(fmt.Sprintf("Environment %s can only have letters, numbers, dashes and underscores", environment)))
+	}
+
+	tagsOverlay := data.OverlayLocation(tagsLocation, environment)
+	parametersOverlay := data.OverlayLocation(parametersLocation, environment)
+
+	if !data.FileExists(tagsOverlay) && !data.FileExists(parametersOverlay) {
+		return nil, nil, nil, errors.New(colors.Error(fmt.Sprintf("Could not find %s or %s for environment %s", parametersOverlay, tagsOverlay, environment)))
+	}
+
+	tags, effectiveTags, err := data.GetEnvironmentTags(tagsLocation, environment)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	parameters, effectiveParameters, err := data.GetEnvironmentParameters(parametersLocation, environment)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if parsed, err := template.Parse(contents); err == nil {
+		for i, value := range effectiveParameters {
+			if parameter, ok := parsed.Parameter(value.Key); ok && parameter.NoEcho {
+				effectiveParameters[i].Value = "****"
+			}
+		}
+	}
+
+	return tags, parameters, &data.Environment{
+		Name:       environment,
+		Parameters: effectiveParameters,
+		Tags:       effectiveTags,
+	}, nil
+}
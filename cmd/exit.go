@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	exitCodeFailed    int = 2
+	exitCodeNoChanges int = 3
+	exitCodeDeclined  int = 4
+	exitCodeLintError int = 5
+	exitCodePolicy    int = 6
+)
+
+var (
+	ciFlag = &cli.BoolFlag{
+		Name:  "ci",
+		Usage: "Prints plain line-oriented output instead of the interactive display. Default when stdout isn't a terminal",
+	}
+
+	yesFlag = &cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "Approves the operation without a prompt in CI or JSON mode",
+	}
+
+	allowDestructiveFlag = &cli.BoolFlag{
+		Name:  "allow-destructive",
+		Usage: "Allows --yes to approve removing or replacing stateful resources",
+	}
+
+	statefulTypeFlag = &cli.StringSliceFlag{
+		Name:  "stateful-type",
+		Usage: "Adds a resource `type` whose removal or replacement needs a typed confirmation. Can be repeated",
+	}
+
+	lintConfigFlag = &cli.StringFlag{
+		Name:  "lint-config",
+		Value: "./lint.json",
+		Usage: "Specifies location of lint configuration `file`, which disables rules or overrides their severity",
+	}
+
+	outputFlag = &cli.StringFlag{
+		Name:  "output",
+		Value: "text",
+		Usage: "Output `format`, text or json. JSON prints the change set, events and result as newline delimited JSON",
+	}
+)
+
+// handleResult converts the outcome of an operation into an exit code. Fatal errors exit with 1, failed operations with 2, change sets without
+// changes with 3, declined operations with 4, templates with lint errors with 5 and templates that break policy rules with 6.
+// Detaching from a running operation isn't a failure
+func handleResult(err error, mode OutputMode) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, cfn.ErrNoChanges):
+		fmt.Fprintln(mode.statusWriter(), colors.Status("No changes to deploy"))
+		return cli.Exit("", exitCodeNoChanges)
+	case errors.Is(err, ErrLintBlocked):
+		fmt.Fprintln(mode.statusWriter(), colors.Error("Template has lint errors. Fix them, or use --skip-lint to deploy anyway"))
+		return cli.Exit("", exitCodeLintError)
+	case errors.Is(err, ErrLintFailed):
+		fmt.Fprintln(mode.statusWriter(), colors.Error("Template has lint errors"))
+		return cli.Exit("", exitCodeLintError)
+	case errors.Is(err, ErrPolicyViolated):
+		fmt.Fprintln(mode.statusWriter(), colors.Error("Template breaks policy rules. Fix the resources above before deploying"))
+		return cli.Exit("", exitCodePolicy)
+	case errors.Is(err, engine.ErrDeclined):
+		return cli.Exit("", exitCodeDeclined)
+	case errors.Is(err, engine.ErrOperationFailed):
+		return cli.Exit("", exitCodeFailed)
+	case errors.Is(err, engine.ErrDetached):
+		fmt.Fprintln(mode.statusWriter(), colors.Status("Stopped watching the stack. The operation continues in CloudFormation"))
+
+		var pending engine.PendingPolicyError
+		if errors.As(err, &pending) {
+			fmt.Fprintln(mode.statusWriter(), colors.Status("The stack policy is set once the operation finishes, which cirrus can't do after it stops watching. Once it finishes, run:"))
+			fmt.Fprintln(mode.statusWriter(), setStackPolicyCommand(pending))
+		}
+
+		return nil
+	}
+
+	fmt.Fprintln(mode.statusWriter(), colors.Error("Cirrus encountered a fatal error:"))
+	return err
+}
+
+// setStackPolicyCommand returns the AWS CLI command that sets a pending stack policy, with the policy quoted for the shell
+func setStackPolicyCommand(pending engine.PendingPolicyError) string {
+	var policy bytes.Buffer
+	if err := json.Compact(&policy, pending.Policy); err != nil {
+		policy.Reset()
+		policy.Write(pending.Policy)
+	}
+
+	quoted := "'" + strings.ReplaceAll(policy.String(), "'", `'\''`) + "'"
+
+	return fmt.Sprintf("  aws cloudformation set-stack-policy --stack-name %s --stack-policy-body %s", pending.Info.StackName, quoted)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/data"
+)
+
+// checkExports blocks an operation that would remove or change exports other stacks import, since CloudFormation only fails it after it starts.
+// Without a template, the stack is being deleted and every imported export breaks
+func checkExports(info data.StackInfo, template []byte) error {
+	dependencies, err := cfn.GetExportDependencies(info)
+	if err != nil {
+		return err
+	}
+
+	broken := data.ImportedExports(dependencies)
+	verb := "deleted"
+
+	if template != nil {
+		broken, err = data.BrokenExports(dependencies, template)
+		if err != nil {
+			return err
+		}
+
+		verb = "updated"
+	}
+
+	if len(broken) == 0 {
+		return nil
+	}
+
+	msg := colors.Error(fmt.Sprintf("Stack %s can't be %s because other stacks import exports it would remove or change:", info.StackName, verb)) + "\n"
+	for _, dependency := range broken {
+		msg += fmt.Sprintf("  %s (output %s) is imported by %s\n", colors.Teal(dependency.ExportName), dependency.OutputKey, strings.Join(dependency.Importers, ", "))
+	}
+	msg += "Remove the imports from those stacks before changing the exports"
+
+	return errors.New(msg)
+}
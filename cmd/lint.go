@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/lint"
+	"github.com/blueseph/cirrus/spec"
+	"github.com/blueseph/cirrus/ui"
+	"github.com/blueseph/cirrus/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var lintFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "template",
+		Aliases: []string{"t"},
+		Value:   "./template.yaml",
+		Usage:   "Specifies location of template `file`",
+	},
+	lintConfigFlag,
+	&cli.StringFlag{
+		Name:  "output",
+		Value: "text",
+		Usage: "Output `format`, text or json. JSON prints the findings and suppressed findings as a single JSON object",
+	},
+}
+
+// LintCommand returns the CLI construct that lints a template without deploying it
+var LintCommand = &cli.Command{
+	Name:   "lint",
+	Usage:  "Check a CloudFormation template for mistakes without deploying it",
+	Action: lintAction,
+	Flags:  lintFlags,
+}
+
+// cfnLintHint is shown when cfn-lint isn't installed, since its rules catch much more than the built-in rules
+const cfnLintHint string = "cfn-lint wasn't found, so only the built-in rules ran. Install it with `pip install cfn-lint` or `brew install cfn-lint` to check the template against its rules too"
+
+// ErrLintFailed is returned when linting finds errors in the template
+var ErrLintFailed = errors.New("template has lint errors")
+
+// ErrLintBlocked is returned when lint errors stop a template from being deployed
+var ErrLintBlocked = fmt.Errorf("%w, so it wasn't deployed", ErrLintFailed)
+
+func lintAction(c *cli.Context) error {
+	mode, err := getOutputMode(c)
+	if err != nil {
+		return err
+	}
+
+	err = Lint(c.String("template"), c.String("lint-config"), mode)
+
+	return handleResult(err, mode)
+}
+
+// Lint checks a template and prints its findings along with the suppressed findings, as JSON in JSON mode. It fails when the template has errors
+func Lint(location string, configLocation string, mode OutputMode) error {
+	result, err := lintTemplate(location, configLocation, mode)
+	if err != nil {
+		return err
+	}
+
+	if mode == OutputJSON {
+		err := ui.WriteLintFindings(location, result)
+		if err != nil {
+			return err
+		}
+	} else {
+		ui.PrintLintFindings(result)
+	}
+
+	if lint.HasSeverity(result.Findings, lint.SeverityError) {
+		return ErrLintFailed
+	}
+
+	return nil
+}
+
+// lintTemplate checks a template against the built-in rules and the resource specification, along with cfn-lint's rules when it's installed. The lint configuration and the
+// template's suppressions are applied to both. Without cfn-lint, a hint to install it is shown
+func lintTemplate(location string, configLocation string, mode OutputMode) (lint.Result, error) {
+	config, err := lint.GetConfig(configLocation)
+	if err != nil {
+		return lint.Result{}, err
+	}
+
+	template, err := ioutil.ReadFile(location)
+	if err != nil {
+		return lint.Result{}, err
+	}
+
+	specification, err := spec.Get()
+	if err != nil {
+		return lint.Result{}, err
+	}
+
+	findings := lint.Run(template, specification)
+
+	cfnLintFindings, err := lint.RunCfnLint(location)
+	if errors.Is(err, lint.ErrCfnLintMissing) {
+		fmt.Fprintln(mode.statusWriter(), colors.Status(cfnLintHint))
+	} else if err != nil {
+		return lint.Result{}, err
+	}
+
+	return config.Apply(template, append(findings, cfnLintFindings...)), nil
+}
+
+// lintBeforeDeploy checks a template before it's deployed. Errors block the deployment and warnings need confirmation, unless approved outside the
+// interactive display
+func lintBeforeDeploy(location string, configLocation string, mode OutputMode, approve bool) error {
+	result, err := lintTemplate(location, configLocation, mode)
+	if err != nil {
+		return err
+	}
+
+	blocked := lint.HasSeverity(result.Findings, lint.SeverityError)
+	warned := lint.HasSeverity(result.Findings, lint.SeverityWarning)
+
+	if len(result.Findings) == 0 && len(result.Suppressed) == 0 {
+		return nil
+	}
+
+	switch mode {
+	case OutputInteractive:
+		if !blocked && !warned {
+			ui.PrintLintFindings(result)
+			return nil
+		}
+
+		confirmed, err := ui.ShowLintFindings(location, result)
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			return ErrLintBlocked
+		}
+
+		if !confirmed {
+			return engine.ErrDeclined
+		}
+	case OutputJSON:
+		err := ui.WriteLintFindings(location, result)
+		if err != nil {
+			return err
+		}
+
+		if blocked {
+			return ErrLintBlocked
+		}
+
+		if warned && !approve {
+			fmt.Fprintln(mode.statusWriter(), "Use --yes to deploy despite lint warnings with JSON output")
+			return engine.ErrDeclined
+		}
+	default:
+		ui.PrintLintFindings(result)
+
+		if blocked {
+			return ErrLintBlocked
+		}
+
+		if warned && !approve {
+			confirmed, err := utils.AskYesNoQuestion("Continue despite lint warnings? [Y/N]")
+			if err != nil {
+				return err
+			}
+
+			if !confirmed {
+				return engine.ErrDeclined
+			}
+		}
+	}
+
+	return nil
+}
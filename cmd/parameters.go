@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/template"
+)
+
+// checkParameters checks the parameters file against the template's Parameters section before creating a change set, since CloudFormation only
+// reports the first problem it finds. Every problem is reported at once, along with the parameter's description and constraint description
+func checkParameters(contents []byte, parameters []cloudformation.Parameter) error {
+	parsed, err := template.Parse(contents)
+	if err != nil {
+		return errors.New(colors.Error(fmt.Sprintf("Unable to parse template. %s", err)))
+	}
+
+	problems := parsed.ValidateParameters(parameters)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	msg := colors.Error("Parameters don't match the template:") + "\n"
+	for _, problem := range problems {
+		msg += fmt.Sprintf("  %s %s\n", colors.Teal(problem.Name), problem.Message)
+
+		explanation := make([]string, 0)
+		for _, description := range []string{problem.Description, problem.ConstraintDescription} {
+			if description != "" {
+				explanation = append(explanation, strings.TrimSuffix(description, "."))
+			}
+		}
+
+		if len(explanation) > 0 {
+			msg += fmt.Sprintf("    %s\n", strings.Join(explanation, ". "))
+		}
+	}
+	msg += "Fix the parameters file, or the template's Parameters section"
+
+	return errors.New(msg)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/policy"
+	"github.com/blueseph/cirrus/ui"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrPolicyViolated is returned when a template breaks a policy rule
+var ErrPolicyViolated = errors.New("template breaks policy rules")
+
+var policyFlag = &cli.StringSliceFlag{
+	Name:  "policy",
+	Value: cli.NewStringSlice("./policy.yaml"),
+	Usage: "Specifies location of a policy rules `file` the template must pass before deploying. Can be repeated",
+}
+
+// getPolicies loads the policy rules files. The default file is optional, but files given as flags have to exist
+func getPolicies(c *cli.Context) ([]policy.Rule, error) {
+	return policy.GetPolicies(c.StringSlice("policy"), c.IsSet("policy"))
+}
+
+// checkPolicies evaluates the template against the policy rules before deploying, with its parameters and stack tags resolved. Any violation blocks
+// the deployment
+func checkPolicies(rules []policy.Rule, mode OutputMode, template []byte, tags []cloudformation.Tag, parameters []cloudformation.Parameter) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	violations, err := policy.Evaluate(template, parameters, tags, rules)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		fmt.Fprintln(mode.statusWriter(), colors.Status(fmt.Sprintf("Template passes %d policy rules", len(rules))))
+		return nil
+	}
+
+	if mode == OutputJSON {
+		err := ui.WritePolicyViolations(violations)
+		if err != nil {
+			return err
+		}
+	} else {
+		ui.PrintPolicyViolations(violations)
+	}
+
+	return ErrPolicyViolated
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/urfave/cli/v2"
+)
+
+var protectFlags = []cli.Flag{
+	stackFlag,
+	envFlag,
+	stackPatternFlag,
+	stackTemplateFlag,
+}
+
+// ProtectCommand returns the CLI construct that enables termination protection on a stack
+var ProtectCommand = &cli.Command{
+	Name:   "protect",
+	Usage:  "Enable termination protection on a CloudFormation stack",
+	Action: protectActionFn(true),
+	Flags:  protectFlags,
+}
+
+// UnprotectCommand returns the CLI construct that disables termination protection on a stack
+var UnprotectCommand = &cli.Command{
+	Name:   "unprotect",
+	Usage:  "Disable termination protection on a CloudFormation stack",
+	Action: protectActionFn(false),
+	Flags:  protectFlags,
+}
+
+func protectActionFn(enabled bool) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		stackName, err := getStackName(c)
+		if err != nil {
+			return err
+		}
+
+		return Protect(stackName, enabled)
+	}
+}
+
+// Protect enables or disables termination protection on a stack
+func Protect(stackName string, enabled bool) error {
+	err := cfn.VerifyAWSCredentials()
+	if err != nil {
+		return err
+	}
+
+	exists, err := cfn.DetermineIfStackExists(stackName)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.New(colors.Error(fmt.Sprintf("Could not find stack %s", stackName)))
+	}
+
+	err = cfn.SetTerminationProtection(stackName, enabled)
+	if err != nil {
+		return err
+	}
+
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+
+	fmt.Println(colors.Status(fmt.Sprintf("Termination protection %s for %s", state, stackName)))
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/ui"
+	"github.com/blueseph/cirrus/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// OutputMode determines how an operation is presented
+type OutputMode string
+
+const (
+	// OutputInteractive shows the interactive display
+	OutputInteractive OutputMode = "interactive"
+
+	// OutputText prints plain line-oriented output
+	OutputText OutputMode = "text"
+
+	// OutputJSON prints the change set, events and result as newline delimited JSON
+	OutputJSON OutputMode = "json"
+)
+
+// Guard determines which changes are destructive, which stacks are protected from deletion, which stack policies apply and how operations are
+// approved outside the interactive display
+type Guard struct {
+	Approve          bool
+	AllowDestructive bool
+	StatefulTypes    []string
+	Policy           data.GuardPolicy
+	StackPolicy      []byte
+	OverridePolicy   []byte
+}
+
+// getGuard reads the approval flags, the guard policy and the stack policies. Stateful types given as flags are added to the default stateful types.
+// An override policy has to exist when it's given
+func getGuard(c *cli.Context) (Guard, error) {
+	policy, err := data.GetGuardPolicy(c.String("guard-policy"))
+	if err != nil {
+		return Guard{}, err
+	}
+
+	stackPolicy, err := data.GetStackPolicy(stackFileLocation(c, "stack-policy"))
+	if err != nil {
+		return Guard{}, err
+	}
+
+	overridePolicy, err := data.GetStackPolicy(c.String("stack-policy-override"))
+	if err != nil {
+		return Guard{}, err
+	}
+
+	if c.String("stack-policy-override") != "" && overridePolicy == nil {
+		return Guard{}, errors.New(colors.Error(fmt.Sprintf("Could not find stack policy override %s", c.String("stack-policy-override"))))
+	}
+
+	return Guard{
+		Approve:          c.Bool("yes"),
+		AllowDestructive: c.Bool("allow-destructive"),
+		StatefulTypes:    append(append([]string{}, data.StatefulResourceTypes...), c.StringSlice("stateful-type")...),
+		Policy:           policy,
+		StackPolicy:      stackPolicy,
+		OverridePolicy:   overridePolicy,
+	}, nil
+}
+
+// getOutputMode determines the output mode from the output and ci flags. Text is the default when stdout isn't a terminal
+func getOutputMode(c *cli.Context) (OutputMode, error) {
+	switch c.String("output") {
+	case "json":
+		return OutputJSON, nil
+	case "text":
+		if c.Bool("ci") || !utils.IsTerminal(os.Stdout) {
+			return OutputText, nil
+		}
+
+		return OutputInteractive, nil
+	}
+
+	return "", fmt.Errorf("unknown output format %s, expected text or json", c.String("output"))
+}
+
+// statusWriter returns where status messages are written. JSON output keeps stdout for JSON only
+func (mode OutputMode) statusWriter() io.Writer {
+	if mode == OutputJSON {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// newRenderer returns the renderer for the output mode along with the context the engine runs in. Closing the interactive display cancels the
+// context, so the engine stops watching the stack
+func newRenderer(mode OutputMode, guard Guard) (context.Context, context.CancelFunc, engine.Renderer) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	switch mode {
+	case OutputJSON:
+		return ctx, cancel, &ui.JSONRenderer{Approve: guard.Approve, AllowDestructive: guard.AllowDestructive}
+	case OutputText:
+		return ctx, cancel, ui.StreamRenderer{Approve: guard.Approve, AllowDestructive: guard.AllowDestructive}
+	}
+
+	return ctx, cancel, ui.NewInteractiveRenderer(cancel)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/spec"
+	"github.com/urfave/cli/v2"
+)
+
+// SpecCommand returns the CLI construct that shows and updates the resource specification properties are validated against
+var SpecCommand = &cli.Command{
+	Name:   "spec",
+	Usage:  "Show the CloudFormation resource specification used to validate properties",
+	Action: specAction,
+	Subcommands: []*cli.Command{
+		{
+			Name:   "update",
+			Usage:  "Replace the resource specification with a CloudFormationResourceSpecification.json downloaded from AWS",
+			Action: specUpdateAction,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Usage:    "Specifies location of specification `file`",
+					Required: true,
+				},
+			},
+		},
+		{
+			Name:   "reset",
+			Usage:  "Go back to the resource specification bundled with cirrus",
+			Action: specResetAction,
+		},
+	},
+}
+
+func specAction(c *cli.Context) error {
+	specification, err := spec.Get()
+	if err != nil {
+		return err
+	}
+
+	printSpecification(specification)
+
+	return nil
+}
+
+func specUpdateAction(c *cli.Context) error {
+	specification, err := spec.Update(c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(colors.Status("Resource specification updated"))
+	printSpecification(specification)
+
+	return nil
+}
+
+func specResetAction(c *cli.Context) error {
+	err := spec.Reset()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(colors.Status("Using the bundled resource specification"))
+
+	return nil
+}
+
+// printSpecification prints where a specification comes from, its version and how many resource types it covers. The bundled specification
+// isn't a published version
+func printSpecification(specification *spec.Specification) {
+	fmt.Printf("Source: %s\n", specification.Source)
+	version := specification.ResourceSpecificationVersion
+	if version == "" {
+		version = "none, a subset of the published specification covering common resource types"
+	}
+
+	fmt.Printf("Version: %s\n", version)
+	fmt.Printf("Resource types: %d\n", len(specification.ResourceTypes))
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/data"
+	"github.com/urfave/cli/v2"
+)
+
+var statusFlags = []cli.Flag{
+	stackFlag,
+	envFlag,
+	stackPatternFlag,
+	stackTemplateFlag,
+}
+
+// StatusCommand returns the CLI construct that shows the state of a stack
+var StatusCommand = &cli.Command{
+	Name:   "status",
+	Usage:  "Show the status, termination protection and stack policy of a CloudFormation stack",
+	Action: statusAction,
+	Flags:  statusFlags,
+}
+
+func statusAction(c *cli.Context) error {
+	stackName, err := getStackName(c)
+	if err != nil {
+		return err
+	}
+
+	return Status(stackName)
+}
+
+// Status prints the status of a stack along with its termination protection and active stack policy
+func Status(stackName string) error {
+	err := cfn.VerifyAWSCredentials()
+	if err != nil {
+		return err
+	}
+
+	exists, err := cfn.DetermineIfStackExists(stackName)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.New(colors.Error(fmt.Sprintf("Could not find stack %s", stackName)))
+	}
+
+	stack, err := cfn.GetStack(stackName)
+	if err != nil {
+		return err
+	}
+
+	details := stack.DescribeStacksOutput.Stacks[0]
+	info := data.StackInfo{StackName: stackName, StackID: *details.StackId}
+
+	policy, err := cfn.GetStackPolicy(info)
+	if err != nil {
+		return err
+	}
+
+	protection := "disabled"
+	if details.EnableTerminationProtection != nil && *details.EnableTerminationProtection {
+		protection = "enabled"
+	}
+
+	updated := details.CreationTime
+	if details.LastUpdatedTime != nil {
+		updated = details.LastUpdatedTime
+	}
+
+	fmt.Printf("Stack:                  %s\n", stackName)
+	fmt.Printf("Id:                     %s\n", info.StackID)
+	fmt.Printf("Status:                 %s\n", details.StackStatus)
+	if details.StackStatusReason != nil {
+		fmt.Printf("Reason:                 %s\n", *details.StackStatusReason)
+	}
+	fmt.Printf("Last updated:           %s\n", updated.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("Termination protection: %s\n", protection)
+	fmt.Printf("Stack policy:           %s\n", formatStackPolicy(policy))
+
+	return nil
+}
+
+// formatStackPolicy indents a stack policy for display
+func formatStackPolicy(policy []byte) string {
+	if len(policy) == 0 {
+		return "none"
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, policy, "", "  "); err != nil {
+		return string(policy)
+	}
+
+	return "\n" + indented.String()
+}
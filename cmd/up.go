@@ -1,18 +1,20 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"time"
-	"unicode"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/blueseph/cirrus/cfn"
 	"github.com/blueseph/cirrus/colors"
 	"github.com/blueseph/cirrus/data"
-	"github.com/blueseph/cirrus/ui"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/report"
+	"github.com/blueseph/cirrus/security"
+	"github.com/blueseph/cirrus/utils"
 	"github.com/urfave/cli/v2"
 )
 
@@ -35,21 +37,42 @@ var upFlags = []cli.Flag{
 		Usage: "Specifies location of tags `file`",
 	},
 	&cli.StringFlag{
-		Name:     "stack",
-		Aliases:  []string{"s"},
-		Usage:    "Specifies `stack name`",
-		Required: true,
+		Name:  "template-config",
+		Usage: "Specifies location of a CodePipeline template configuration `file` with the parameters, tags and stack policy",
 	},
+	stackFlag,
+	envFlag,
+	stackPatternFlag,
 	&cli.BoolFlag{
 		Name:    "skip-lint",
 		Aliases: []string{"sl"},
 		Usage:   "Skips linting (not recommended)",
 	},
+	lintConfigFlag,
+	policyFlag,
 	&cli.BoolFlag{
 		Name:    "overwrite",
 		Aliases: []string{"o"},
 		Usage:   "Overwrites existing empty (0 resource) stacks before updating",
 	},
+	&cli.StringFlag{
+		Name:  "stack-policy",
+		Value: "./stack-policy.json",
+		Usage: "Specifies location of stack policy `file`, applied on create and update",
+	},
+	&cli.StringFlag{
+		Name:  "stack-policy-override",
+		Usage: "Temporarily replaces the stack policy with the policy in `file` for this update only. The stack policy is restored once the update finishes, so if cirrus stops watching or is killed before then, the override stays until the stack policy is set again",
+	},
+	&cli.StringFlag{
+		Name:  "report",
+		Usage: "Writes a Markdown or HTML (by extension) report of the change set to `file` before it's reviewed",
+	},
+	ciFlag,
+	yesFlag,
+	allowDestructiveFlag,
+	statefulTypeFlag,
+	outputFlag,
 }
 
 // UpCommand returns the CLI construct that uploads a template to CloudFormation and watches the response
@@ -61,35 +84,99 @@ var UpCommand = &cli.Command{
 }
 
 func upAction(c *cli.Context) error {
-	template, err := ioutil.ReadFile(c.String("template"))
+	contents, err := ioutil.ReadFile(c.String("template"))
+	if err != nil {
+		return err
+	}
+
+	err = checkTemplateConfig(c)
+	if err != nil {
+		return err
+	}
+
+	tags, parameters, environment, err := getStackFiles(c, contents)
 	if err != nil {
 		return err
 	}
 
-	tags, err := data.GetTags(c.String("tags"))
+	mode, err := getOutputMode(c)
 	if err != nil {
 		return err
 	}
 
-	parameters, err := data.GetParameters(c.String("parameters"))
+	stack, err := getStackName(c)
 	if err != nil {
 		return err
 	}
 
-	stack := c.String("stack")
 	overwrite := c.Bool("overwrite")
+	guard, err := getGuard(c)
+	if err != nil {
+		return err
+	}
+
+	reportLocation := c.String("report")
 
-	err = Up(stack, overwrite, template, tags, parameters)
+	rules, err := getPolicies(c)
 	if err != nil {
-		fmt.Println(colors.Error("Cirrus encountered a fatal error:"))
 		return err
 	}
 
+	if !c.Bool("skip-lint") {
+		err := lintBeforeDeploy(c.String("template"), c.String("lint-config"), mode, guard.Approve)
+		if err != nil {
+			return handleResult(err, mode)
+		}
+	}
+
+	err = checkParameters(contents, parameters)
+	if err != nil {
+		return handleResult(err, mode)
+	}
+
+	err = checkPolicies(rules, mode, contents, tags, parameters)
+	if err != nil {
+		return handleResult(err, mode)
+	}
+
+	err = Up(stack, overwrite, mode, guard, reportLocation, contents, tags, parameters, environment)
+
+	return handleResult(err, mode)
+}
+
+// checkTemplateConfig makes sure a template configuration given as a flag exists, and isn't combined with the files it replaces
+func checkTemplateConfig(c *cli.Context) error {
+	location := c.String("template-config")
+	if location == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(location); err != nil {
+		return errors.New(colors.Error(fmt.Sprintf("Could not find template configuration %s", location)))
+	}
+
+	for _, name := range []string{"parameters", "tags", "stack-policy"} {
+		if c.IsSet(name) {
+			return errors.New(colors.Error(fmt.Sprintf("--template-config holds the parameters, tags and stack policy, so it can't be used with --%s", name)))
+		}
+	}
+
 	return nil
 }
 
-// Up kicks off the stack creation lifecycle, creating a change set, confirming the change set, and tailing the events.
-func Up(stackName string, overwrite bool, template []byte, tags []cloudformation.Tag, parameters []cloudformation.Parameter) error {
+// stackFileLocation returns the location of the parameters, tags or stack policy file, which is the template configuration when one is given
+func stackFileLocation(c *cli.Context, name string) string {
+	if location := c.String("template-config"); location != "" {
+		return location
+	}
+
+	return c.String(name)
+}
+
+// Up kicks off the stack creation lifecycle, creating a change set, confirming the change set along with a security summary of the template and
+// the effective parameters and tags of the environment, if any, and tailing the events. Outside the interactive display the change set is approved
+// by the guard, or a prompt in text mode. If a report location is given, a change set report is written there first.
+func Up(stackName string, overwrite bool, mode OutputMode, guard Guard, reportLocation string, template []byte, tags []cloudformation.Tag, parameters []cloudformation.Parameter, environment *data.Environment) error {
 	changeSetName := stackName + "-" + fmt.Sprint(time.Now().Unix())
 
 	info := data.StackInfo{
@@ -109,29 +196,59 @@ func Up(stackName string, overwrite bool, template []byte, tags []cloudformation
 
 	empty := cfn.DetermineIfStackIsEmpty(info)
 
+	if exists && !empty {
+		err := checkExports(info, template)
+		if err != nil {
+			return err
+		}
+	}
+
 	if exists && empty {
-		err := handleOverwrite(overwrite, exists, info)
+		err := handleOverwrite(overwrite, mode, info)
 		if err != nil {
 			return err
 		}
 	}
 
-	fmt.Println(colors.Status("Creating change set..."))
-	changeSet, err := cfn.CreateChanges(info, template, tags, parameters, exists)
+	fmt.Fprintln(mode.statusWriter(), colors.Status("Creating change set..."))
+	changeSet, nestedChanges, err := cfn.CreateChanges(info, template, tags, parameters, exists)
 	if err != nil {
 		return err
 	}
 
 	info.StackID = *changeSet.StackId
 
+	findings, err := security.Analyze(template, security.AccountFromARN(info.StackID))
+	if err != nil {
+		return discardChangeSet(info, err)
+	}
+
 	operation := cfn.StackOperationCreate
 	if exists {
 		operation = cfn.StackOperationUpdate
 	}
 
-	err = ui.DisplayChanges(info, changeSet, operation)
+	if reportLocation != "" {
+		err := report.Write(reportLocation, report.New(info, operation, changeSet.Changes, nestedChanges, guard.StatefulTypes))
+		if err != nil {
+			return discardChangeSet(info, err)
+		}
 
-	if err == nil {
+		fmt.Fprintln(mode.statusWriter(), colors.Status(fmt.Sprintf("Change set report written to %s", reportLocation)))
+	}
+
+	ctx, cancel, renderer := newRenderer(mode, guard)
+	defer cancel()
+
+	err = engine.RunChangeSet(ctx, renderer, info, changeSet, nestedChanges, operation, engine.ChangeSetOptions{
+		StatefulTypes:  guard.StatefulTypes,
+		StackPolicy:    guard.StackPolicy,
+		OverridePolicy: guard.OverridePolicy,
+		Security:       findings,
+		Environment:    environment,
+	})
+
+	if err == nil && mode != OutputJSON {
 		fmt.Println("\nStack Info")
 		fmt.Println("----------")
 		fmt.Printf("Stack Name: %s\n", info.StackName)
@@ -139,54 +256,40 @@ func Up(stackName string, overwrite bool, template []byte, tags []cloudformation
 	}
 
 	return err
-
 }
 
-func askYesNoQuestion(question string) (bool, error) {
-	reader := bufio.NewReader(os.Stdin)
-
-	fmt.Println(question)
-
-	for {
-		char, _, err := reader.ReadRune()
-
-		if err != nil {
-			return false, err
-		}
-
-		char = unicode.ToLower(char)
-
-		switch char {
-		case 'y':
-			return true, nil
-		case 'n':
-			return false, nil
-		default:
-			fmt.Println("Please enter Y/N")
-		}
+// discardChangeSet deletes a change set that can't be reviewed because of an error, so it isn't left on the stack. The error is returned either way
+func discardChangeSet(info data.StackInfo, err error) error {
+	if deleteErr := cfn.DeleteChangeSet(info); deleteErr != nil {
+		fmt.Fprintln(os.Stderr, colors.Error(fmt.Sprintf("Unable to delete change set %s. %s", info.ChangeSetName, deleteErr)))
 	}
+
+	return err
 }
 
-func handleOverwrite(overwrite bool, exists bool, info data.StackInfo) error {
+func handleOverwrite(overwrite bool, mode OutputMode, info data.StackInfo) error {
 	var err error
 	confirm := overwrite
 
 	if !confirm {
-		confirm, err = askYesNoQuestion(colors.Status("Empty stack detected. Overwrite? [Y/N]"))
+		if mode == OutputJSON {
+			return errors.New(colors.Error("Empty stack detected. Use --overwrite to replace it with JSON output"))
+		}
+
+		confirm, err = utils.AskYesNoQuestion(colors.Status("Empty stack detected. Overwrite? [Y/N]"))
 		if err != nil {
 			return err
 		}
 	}
 
 	if confirm {
-		fmt.Println(colors.Status("Deleting stack..."))
+		fmt.Fprintln(mode.statusWriter(), colors.Status("Deleting stack..."))
 		err := cfn.DeleteStackAndWait(info)
-		exists = false
 		if err != nil {
 			return err
 		}
 	} else {
-		fmt.Println(colors.Status("User declined empty stack deletion. Terminating"))
+		fmt.Fprintln(mode.statusWriter(), colors.Status("User declined empty stack deletion. Terminating"))
 		return nil
 	}
 
@@ -2,10 +2,8 @@ package data
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
@@ -23,6 +21,8 @@ type DisplayRow struct {
 	Action            cloudformation.ChangeAction
 	Source            DisplayRowSource
 	Active            bool
+	Parent            string
+	DeletionPolicy    DeletionPolicy
 }
 
 //StackInfo is a normalized data structure to store identifier properties of a stack/change set
@@ -32,6 +32,9 @@ type StackInfo struct {
 	StackName     string
 }
 
+//NestedStackSeparator separates the logical IDs of a nested stack resource's ancestors in its display row key
+const NestedStackSeparator string = "/"
+
 //DisplayRowSource is an enum to determine the origin of the display row
 type DisplayRowSource string
 
@@ -114,6 +117,23 @@ func ChangeMap(changes []cloudformation.Change, active bool) map[string]DisplayR
 	return mapChanges
 }
 
+//NestedChangeMap normalizes the changes of nested change sets, keyed by the display row key of their nested stack, into a map of DisplayRows
+func NestedChangeMap(nestedChanges map[string][]cloudformation.Change, active bool) map[string]DisplayRow {
+	mapChanges := make(map[string]DisplayRow)
+
+	for parent, changes := range nestedChanges {
+		for _, change := range changes {
+			row := CreateDisplayRowFromChange(change, active)
+			row.LogicalResourceID = NestedRowKey(parent, row.LogicalResourceID)
+			row.Parent = parent
+
+			mapChanges[row.LogicalResourceID] = row
+		}
+	}
+
+	return mapChanges
+}
+
 //CreateDisplayRowFromChange normalizes a cloudformation change into a display row
 func CreateDisplayRowFromChange(change cloudformation.Change, active bool) DisplayRow {
 	return DisplayRow{
@@ -148,6 +168,20 @@ func CreateDisplayRowFromEvent(event cloudformation.StackEvent) DisplayRow {
 	}
 }
 
+//NestedRowKey returns the display row key of a resource that belongs to the nested stack row with the given key
+func NestedRowKey(parent string, logicalID string) string {
+	if parent == "" {
+		return logicalID
+	}
+
+	return parent + NestedStackSeparator + logicalID
+}
+
+//IsStackEvent determines if an event describes the stack itself rather than one of its resources
+func IsStackEvent(info StackInfo, event cloudformation.StackEvent) bool {
+	return *event.ResourceType == CloudformationStackResource && event.PhysicalResourceId != nil && *event.PhysicalResourceId == info.StackID
+}
+
 //ResourceMap normalizes a slice of resource summaries into a map of DisplayRows
 func ResourceMap(resources []cloudformation.StackResourceSummary) map[string]DisplayRow {
 	mapResources := make(map[string]DisplayRow)
@@ -191,42 +225,45 @@ func GetResourcesFromPaginator(paginator *cloudformation.ListStackResourcesPagin
 	return resources
 }
 
-// GetTags gets the tags from the location provided. If tags don't exist, return an empty tag slice
+// GetTags gets the tags from the location provided. Tags are a list of Key and Value objects, an object of keys and values, or the Tags of a
+// CodePipeline template configuration, in JSON or YAML. If tags don't exist, return an empty tag slice
 func GetTags(location string) ([]cloudformation.Tag, error) {
-	invalidJSON := "Unable to load tags. tags must be valid JSON and only of type string"
 	docsMessage := "https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-properties-resource-tags.html"
-	errorMessage := fmt.Sprintf("%s \n %s", colors.Error(invalidJSON), colors.Docs(docsMessage))
 
-	container := make([]cloudformation.Tag, 0)
-
-	tags, err := ioutil.ReadFile(location)
+	tags, err := readTags(location)
 	if err != nil {
-		return container, nil
-	}
-
-	if err := json.Unmarshal(tags, &container); err != nil {
-		return nil, errors.New(errorMessage)
+		invalidFile := fmt.Sprintf("Unable to load tags from %s. %s", location, err)
+		return nil, errors.New(fmt.Sprintf("%s \n %s", colors.Error(invalidFile), colors.Docs(docsMessage)))
 	}
 
-	return container, nil
+	return tags, nil
 }
 
-// GetParameters gets the tags from the location provided. If tags don't exist, return an empty tag slice
+// GetParameters gets the parameters from the location provided. Parameters are a list of ParameterKey and ParameterValue objects, an object of
+// keys and values, or the Parameters of a CodePipeline template configuration, in JSON or YAML. If parameters don't exist, return an empty
+// parameter slice
 func GetParameters(location string) ([]cloudformation.Parameter, error) {
-	invalidJSON := "Unable to load parameters. Parameters must be valid JSON and only of type string"
 	docsMessage := "https://aws.amazon.com/blogs/devops/passing-parameters-to-cloudformation-stacks-with-the-aws-cli-and-powershell/"
-	errorMessage := fmt.Sprintf("%s \n %s", colors.Error(invalidJSON), colors.Docs(docsMessage))
 
-	container := make([]cloudformation.Parameter, 0)
-
-	parameters, err := ioutil.ReadFile(location)
+	parameters, err := readParameters(location)
 	if err != nil {
-		return container, nil
+		invalidFile := fmt.Sprintf("Unable to load parameters from %s. %s", location, err)
+		return nil, errors.New(fmt.Sprintf("%s \n %s", colors.Error(invalidFile), colors.Docs(docsMessage)))
 	}
 
-	if err := json.Unmarshal(parameters, &container); err != nil {
-		return nil, errors.New(errorMessage)
+	return parameters, nil
+}
+
+//GetStackPolicy reads a stack policy from the given location, in JSON or YAML, or the StackPolicy of a CodePipeline template configuration. If no
+//policy exists, return nil
+func GetStackPolicy(location string) ([]byte, error) {
+	docsMessage := "https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/protect-stack-resources.html"
+
+	policy, err := readStackPolicy(location)
+	if err != nil {
+		invalidFile := fmt.Sprintf("Unable to load stack policy from %s. %s", location, err)
+		return nil, errors.New(fmt.Sprintf("%s \n %s", colors.Error(invalidFile), colors.Docs(docsMessage)))
 	}
 
-	return container, nil
+	return policy, nil
 }
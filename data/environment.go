@@ -0,0 +1,155 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+)
+
+//EffectiveValue is a parameter or tag once an environment's overlay is layered over the base file, along with the file it was set in
+type EffectiveValue struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+//Environment is a stack's parameters and tags for an environment, such as dev or prod, and the files they come from
+type Environment struct {
+	Name       string
+	Parameters []EffectiveValue
+	Tags       []EffectiveValue
+}
+
+//OverlayLocation returns the location of an environment's overlay of a file, which has the environment before the extension. The overlay of
+//parameters.json for prod is parameters.prod.json
+func OverlayLocation(location string, environment string) string {
+	extension := filepath.Ext(location)
+
+	return strings.TrimSuffix(location, extension) + "." + environment + extension
+}
+
+//FileExists determines if there's a file at a location
+func FileExists(location string) bool {
+	info, err := os.Stat(location)
+
+	return err == nil && !info.IsDir()
+}
+
+//layer merges an overlay's keys over a base's. Keys in the overlay replace the base's, keeping the base's order, and keys only in the overlay
+//come after the base's, in the overlay's order. Keys can't be removed by an overlay
+func layer(base []string, overlay []string) []string {
+	keys := append([]string{}, base...)
+	inBase := make(map[string]bool)
+
+	for _, key := range base {
+		inBase[key] = true
+	}
+
+	for _, key := range overlay {
+		if !inBase[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+//layerFiles reads a file and the environment's overlay of it with the read function, which returns the keys each file sets. It returns the keys
+//of both files layered, along with the file each key is set in
+func layerFiles(location string, environment string, read func(file string) ([]string, error)) ([]string, map[string]string, error) {
+	sources := map[string]string{}
+	files := make([][]string, 0, 2)
+
+	for _, file := range []string{location, OverlayLocation(location, environment)} {
+		keys, err := read(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, key := range keys {
+			sources[key] = file
+		}
+
+		files = append(files, keys)
+	}
+
+	return layer(files[0], files[1]), sources, nil
+}
+
+//GetEnvironmentParameters gets the parameters from the location provided, with the environment's overlay layered over them, along with the file
+//each parameter is set in. Either file may not exist. Parameters using their previous value are shown as such
+func GetEnvironmentParameters(location string, environment string) ([]cloudformation.Parameter, []EffectiveValue, error) {
+	values := map[string]cloudformation.Parameter{}
+
+	keys, sources, err := layerFiles(location, environment, func(file string) ([]string, error) {
+		parameters, err := GetParameters(file)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(parameters))
+		for _, parameter := range parameters {
+			keys = append(keys, *parameter.ParameterKey)
+			values[*parameter.ParameterKey] = parameter
+		}
+
+		return keys, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parameters := make([]cloudformation.Parameter, 0, len(keys))
+	effective := make([]EffectiveValue, 0, len(keys))
+
+	for _, key := range keys {
+		parameter := values[key]
+		value := aws.StringValue(parameter.ParameterValue)
+
+		if parameter.UsePreviousValue != nil && *parameter.UsePreviousValue {
+			value = "(previous value)"
+		}
+
+		parameters = append(parameters, parameter)
+		effective = append(effective, EffectiveValue{Key: key, Value: value, Source: sources[key]})
+	}
+
+	return parameters, effective, nil
+}
+
+//GetEnvironmentTags gets the tags from the location provided, with the environment's overlay layered over them, along with the file each tag is
+//set in. Either file may not exist
+func GetEnvironmentTags(location string, environment string) ([]cloudformation.Tag, []EffectiveValue, error) {
+	values := map[string]cloudformation.Tag{}
+
+	keys, sources, err := layerFiles(location, environment, func(file string) ([]string, error) {
+		tags, err := GetTags(file)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			keys = append(keys, *tag.Key)
+			values[*tag.Key] = tag
+		}
+
+		return keys, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tags := make([]cloudformation.Tag, 0, len(keys))
+	effective := make([]EffectiveValue, 0, len(keys))
+
+	for _, key := range keys {
+		tags = append(tags, values[key])
+		effective = append(effective, EffectiveValue{Key: key, Value: aws.StringValue(values[key].Value), Source: sources[key]})
+	}
+
+	return tags, effective, nil
+}
@@ -0,0 +1,55 @@
+package data
+
+import (
+	"github.com/blueseph/cirrus/template"
+)
+
+//ExportDependency is an export of a stack along with the stacks that import it
+type ExportDependency struct {
+	OutputKey  string
+	ExportName string
+	Value      string
+	Importers  []string
+}
+
+//ImportedExports returns the export dependencies that other stacks import
+func ImportedExports(dependencies []ExportDependency) []ExportDependency {
+	imported := make([]ExportDependency, 0)
+
+	for _, dependency := range dependencies {
+		if len(dependency.Importers) > 0 {
+			imported = append(imported, dependency)
+		}
+	}
+
+	return imported
+}
+
+//changedLiteral determines if a template value is a literal, such as a string, number or boolean, that differs from the deployed value. Values set
+//with intrinsic functions can't be resolved from the template alone, so they're assumed unchanged
+func changedLiteral(value template.Value, deployed string) bool {
+	literal, ok := value.Literal()
+
+	return ok && literal != deployed
+}
+
+//BrokenExports parses a JSON or YAML template and returns the imported exports it removes or changes. Exports whose output, export or literal
+//name or value no longer match are broken
+func BrokenExports(dependencies []ExportDependency, contents []byte) ([]ExportDependency, error) {
+	parsed, err := template.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	broken := make([]ExportDependency, 0)
+
+	for _, dependency := range ImportedExports(dependencies) {
+		output, ok := parsed.Output(dependency.OutputKey)
+
+		if !ok || !output.ExportName.IsSet() || changedLiteral(output.ExportName, dependency.ExportName) || changedLiteral(output.Value, dependency.Value) {
+			broken = append(broken, dependency)
+		}
+	}
+
+	return broken, nil
+}
@@ -0,0 +1,136 @@
+package data
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/utils"
+)
+
+//SortOrder is an enum to determine how display rows are ordered
+type SortOrder string
+
+const (
+	//SortByLogicalID orders display rows alphabetically by logical ID
+	SortByLogicalID SortOrder = "logical id"
+
+	//SortByStatus orders display rows by status, failures first
+	SortByStatus SortOrder = "status"
+
+	//SortByType orders display rows alphabetically by resource type
+	SortByType SortOrder = "type"
+
+	//SortByLastUpdate orders display rows by their latest event, most recent first
+	SortByLastUpdate SortOrder = "last update"
+
+	//SortByAction orders display rows by change action, removals first
+	SortByAction SortOrder = "action"
+)
+
+var (
+	//SortOrders is the order sort orders are cycled through
+	SortOrders []SortOrder = []SortOrder{
+		SortByLogicalID,
+		SortByStatus,
+		SortByType,
+		SortByLastUpdate,
+		SortByAction,
+	}
+
+	actionRank map[cloudformation.ChangeAction]int = map[cloudformation.ChangeAction]int{
+		cloudformation.ChangeActionRemove: 0,
+		cloudformation.ChangeActionModify: 1,
+		cloudformation.ChangeActionAdd:    2,
+	}
+)
+
+//DisplayOptions determines which display rows are shown and in which order
+type DisplayOptions struct {
+	Search        string
+	HideComplete  bool
+	HideUnchanged bool
+	Sort          SortOrder
+	Collapsed     map[string]bool
+}
+
+//NextSortOrder returns the sort order that follows the given one
+func NextSortOrder(order SortOrder) SortOrder {
+	for i, item := range SortOrders {
+		if item == order {
+			return SortOrders[(i+1)%len(SortOrders)]
+		}
+	}
+
+	return SortByLogicalID
+}
+
+func statusRank(row DisplayRow) int {
+	switch {
+	case row.Source != DisplayRowSourceEvent:
+		return 2
+	case utils.ContainsResourceStatus(NegativeEventStatus, row.Status):
+		return 0
+	case utils.ContainsResourceStatus(PendingEventStatus, row.Status):
+		return 1
+	default:
+		return 3
+	}
+}
+
+func matchesSearch(row DisplayRow, search string) bool {
+	search = strings.ToLower(search)
+
+	return strings.Contains(strings.ToLower(row.LogicalResourceID), search) || strings.Contains(strings.ToLower(row.ResourceType), search)
+}
+
+//MatchesDisplayOptions determines if a display row should be shown with the given options. Unchanged rows are rows that have not received an event
+//since the operation started
+func MatchesDisplayOptions(row DisplayRow, options DisplayOptions) bool {
+	if options.Search != "" && !matchesSearch(row, options.Search) {
+		return false
+	}
+
+	if options.HideComplete && row.Source == DisplayRowSourceEvent && !utils.ContainsResourceStatus(PendingEventStatus, row.Status) && !utils.ContainsResourceStatus(NegativeEventStatus, row.Status) {
+		return false
+	}
+
+	if options.HideUnchanged && row.Active && row.Source != DisplayRowSourceEvent {
+		return false
+	}
+
+	return true
+}
+
+//SortDisplayRows returns the display rows in the given sort order without side-effects
+func SortDisplayRows(displayRows []DisplayRow, order SortOrder) []DisplayRow {
+	rows := make([]DisplayRow, len(displayRows))
+	copy(rows, displayRows)
+
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+
+		switch order {
+		case SortByStatus:
+			if statusRank(a) != statusRank(b) {
+				return statusRank(a) < statusRank(b)
+			}
+		case SortByType:
+			if a.ResourceType != b.ResourceType {
+				return a.ResourceType < b.ResourceType
+			}
+		case SortByLastUpdate:
+			if !a.Timestamp.Equal(b.Timestamp) {
+				return a.Timestamp.After(b.Timestamp)
+			}
+		case SortByAction:
+			if actionRank[a.Action] != actionRank[b.Action] {
+				return actionRank[a.Action] < actionRank[b.Action]
+			}
+		}
+
+		return a.LogicalResourceID < b.LogicalResourceID
+	})
+
+	return rows
+}
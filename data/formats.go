@@ -0,0 +1,307 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"gopkg.in/yaml.v3"
+)
+
+//Sections of a CodePipeline template configuration file, which holds the parameters, tags and stack policy of a stack in one file
+const (
+	configParameters  string = "Parameters"
+	configTags        string = "Tags"
+	configStackPolicy string = "StackPolicy"
+)
+
+//fileError is a problem in a parameters, tags or stack policy file, along with the line it's on
+type fileError struct {
+	line    int
+	message string
+}
+
+func (err fileError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.line, err.message)
+}
+
+func newFileError(node *yaml.Node, format string, args ...interface{}) error {
+	return fileError{line: node.Line, message: fmt.Sprintf(format, args...)}
+}
+
+//readDocument reads a JSON or YAML file, along with its contents. Files that don't exist or are empty have no document
+func readDocument(location string) (*yaml.Node, []byte, error) {
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, nil, nil
+	}
+
+	var document yaml.Node
+
+	err = yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, nil, errors.New(strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+
+	if len(document.Content) == 0 {
+		return nil, nil, nil
+	}
+
+	return document.Content[0], contents, nil
+}
+
+//isTemplateConfiguration determines if a document is a CodePipeline template configuration, an object of Parameters, Tags and StackPolicy
+//objects. A map of parameters or tags named after the sections has string values, so it isn't one
+func isTemplateConfiguration(document *yaml.Node) bool {
+	if document.Kind != yaml.MappingNode || len(document.Content) == 0 {
+		return false
+	}
+
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		switch document.Content[i].Value {
+		case configParameters, configTags, configStackPolicy:
+		default:
+			return false
+		}
+
+		if document.Content[i+1].Kind != yaml.MappingNode {
+			return false
+		}
+	}
+
+	return true
+}
+
+//configSection returns a section of a template configuration, or the document itself when it isn't one. A template configuration without the
+//section has none
+func configSection(document *yaml.Node, name string) *yaml.Node {
+	if document == nil || !isTemplateConfiguration(document) {
+		return document
+	}
+
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		if document.Content[i].Value == name {
+			return document.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+//scalar returns the value of a string, number or boolean as a string
+func scalar(node *yaml.Node, name string) (string, error) {
+	if node.Kind != yaml.ScalarNode || node.ShortTag() == "!!null" {
+		return "", newFileError(node, "%s must be a string", name)
+	}
+
+	return node.Value, nil
+}
+
+//keyValue is a key and value read from a list of objects or a map. Known fields set alongside them are kept by the name they're known by
+type keyValue struct {
+	key    string
+	value  *yaml.Node
+	item   *yaml.Node
+	fields map[string]*yaml.Node
+}
+
+//keyValues reads the keys and values of a list of objects with key and value fields, such as [{"Key": "env", "Value": "prod"}], or of a map of
+//keys to values, such as {"env": "prod"}. Fields other than the key and value have to be in the known fields, and are matched without case. Keys can only be set
+//once
+func keyValues(document *yaml.Node, keyField string, valueField string, knownFields ...string) ([]keyValue, error) {
+	found := make([]keyValue, 0)
+	seen := make(map[string]bool)
+
+	add := func(key *yaml.Node, value *yaml.Node, item *yaml.Node, fields map[string]*yaml.Node) error {
+		name, err := scalar(key, keyField)
+		if err != nil {
+			return err
+		}
+
+		if seen[name] {
+			return newFileError(key, "%s is set more than once", name)
+		}
+
+		seen[name] = true
+		found = append(found, keyValue{key: name, value: value, item: item, fields: fields})
+
+		return nil
+	}
+
+	switch document.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(document.Content); i += 2 {
+			err := add(document.Content[i], document.Content[i+1], nil, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range document.Content {
+			if item.Kind != yaml.MappingNode {
+				return nil, newFileError(item, "each item must be an object with %s and %s", keyField, valueField)
+			}
+
+			var key *yaml.Node
+			var value *yaml.Node
+			fields := make(map[string]*yaml.Node)
+
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				field := item.Content[i].Value
+				known, isKnown := knownField(knownFields, field)
+
+				switch {
+				case strings.EqualFold(field, keyField):
+					key = item.Content[i+1]
+				case strings.EqualFold(field, valueField):
+					value = item.Content[i+1]
+				case isKnown:
+					fields[known] = item.Content[i+1]
+				default:
+					return nil, newFileError(item.Content[i], "%s isn't a field, expected %s", field, strings.Join(append([]string{keyField, valueField}, knownFields...), ", "))
+				}
+			}
+
+			if key == nil {
+				return nil, newFileError(item, "item has no %s", keyField)
+			}
+
+			err := add(key, value, item, fields)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, newFileError(document, "must be a list of objects with %s and %s, or an object of keys and values", keyField, valueField)
+	}
+
+	return found, nil
+}
+
+//knownField returns the name a field is known by. Fields are matched without case, as they are when decoding JSON
+func knownField(knownFields []string, field string) (string, bool) {
+	for _, known := range knownFields {
+		if strings.EqualFold(known, field) {
+			return known, true
+		}
+	}
+
+	return "", false
+}
+
+//readParameters reads parameters in any of the supported formats
+func readParameters(location string) ([]cloudformation.Parameter, error) {
+	document, _, err := readDocument(location)
+	if err != nil || document == nil {
+		return make([]cloudformation.Parameter, 0), err
+	}
+
+	section := configSection(document, configParameters)
+	if section == nil {
+		return make([]cloudformation.Parameter, 0), nil
+	}
+
+	values, err := keyValues(section, "ParameterKey", "ParameterValue", "UsePreviousValue", "ResolvedValue")
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := make([]cloudformation.Parameter, 0, len(values))
+
+	for _, value := range values {
+		parameter := cloudformation.Parameter{ParameterKey: aws.String(value.key)}
+
+		if usePrevious := value.fields["UsePreviousValue"]; usePrevious != nil {
+			if usePrevious.ShortTag() != "!!bool" {
+				return nil, newFileError(usePrevious, "UsePreviousValue of %s must be true or false", value.key)
+			}
+
+			parameter.UsePreviousValue = aws.Bool(strings.EqualFold(usePrevious.Value, "true"))
+		}
+
+		switch {
+		case value.value != nil:
+			parameterValue, err := scalar(value.value, value.key)
+			if err != nil {
+				return nil, err
+			}
+
+			parameter.ParameterValue = aws.String(parameterValue)
+		case parameter.UsePreviousValue == nil || !*parameter.UsePreviousValue:
+			return nil, newFileError(value.item, "%s needs a ParameterValue, or UsePreviousValue", value.key)
+		}
+
+		parameters = append(parameters, parameter)
+	}
+
+	return parameters, nil
+}
+
+//readTags reads tags in any of the supported formats
+func readTags(location string) ([]cloudformation.Tag, error) {
+	document, _, err := readDocument(location)
+	if err != nil || document == nil {
+		return make([]cloudformation.Tag, 0), err
+	}
+
+	section := configSection(document, configTags)
+	if section == nil {
+		return make([]cloudformation.Tag, 0), nil
+	}
+
+	values, err := keyValues(section, "Key", "Value")
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]cloudformation.Tag, 0, len(values))
+
+	for _, value := range values {
+		if value.value == nil {
+			return nil, newFileError(value.item, "%s needs a Value", value.key)
+		}
+
+		tagValue, err := scalar(value.value, value.key)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, cloudformation.Tag{Key: aws.String(value.key), Value: aws.String(tagValue)})
+	}
+
+	return tags, nil
+}
+
+//readStackPolicy reads a stack policy, or the StackPolicy of a template configuration, as JSON. JSON policies are kept as they're written
+func readStackPolicy(location string) ([]byte, error) {
+	document, contents, err := readDocument(location)
+	if err != nil || document == nil {
+		return nil, err
+	}
+
+	if !isTemplateConfiguration(document) && json.Valid(contents) {
+		return contents, nil
+	}
+
+	section := configSection(document, configStackPolicy)
+	if section == nil {
+		return nil, nil
+	}
+
+	if section.Kind != yaml.MappingNode {
+		return nil, newFileError(section, "stack policy must be an object")
+	}
+
+	var policy interface{}
+
+	err = section.Decode(&policy)
+	if err != nil {
+		return nil, errors.New(strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+
+	return json.Marshal(policy)
+}
@@ -0,0 +1,213 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/colors"
+)
+
+//StatefulResourceTypes are resource types that hold data which is lost when the resource is removed or replaced
+var StatefulResourceTypes []string = []string{
+	"AWS::DocDB::DBCluster",
+	"AWS::DynamoDB::GlobalTable",
+	"AWS::DynamoDB::Table",
+	"AWS::EC2::Volume",
+	"AWS::EFS::FileSystem",
+	"AWS::ElastiCache::CacheCluster",
+	"AWS::ElastiCache::ReplicationGroup",
+	"AWS::Elasticsearch::Domain",
+	"AWS::FSx::FileSystem",
+	"AWS::Kinesis::Stream",
+	"AWS::KMS::Key",
+	"AWS::Neptune::DBCluster",
+	"AWS::RDS::DBCluster",
+	"AWS::RDS::DBInstance",
+	"AWS::Redshift::Cluster",
+	"AWS::S3::Bucket",
+	"AWS::SQS::Queue",
+}
+
+func isStateful(resourceType string, statefulTypes []string) bool {
+	for _, statefulType := range statefulTypes {
+		if statefulType == resourceType {
+			return true
+		}
+	}
+
+	return false
+}
+
+//DestructiveRows returns the display rows that remove or replace a resource of a stateful type, ordered by logical ID. Removing a resource that's
+//retained or snapshotted keeps its data, so it isn't destructive
+func DestructiveRows(displayRows map[string]DisplayRow, statefulTypes []string) []DisplayRow {
+	destructive := make([]DisplayRow, 0)
+
+	for _, row := range displayRows {
+		if !isStateful(row.ResourceType, statefulTypes) {
+			continue
+		}
+
+		if (row.Action == cloudformation.ChangeActionRemove && !IsRetained(row)) || row.Replacement == cloudformation.ReplacementTrue {
+			destructive = append(destructive, row)
+		}
+	}
+
+	sort.Slice(destructive, func(i, j int) bool {
+		return destructive[i].LogicalResourceID < destructive[j].LogicalResourceID
+	})
+
+	return destructive
+}
+
+//ConfirmsDestructive determines if a typed confirmation is either the stack name or the number of destructive changes
+func ConfirmsDestructive(input string, info StackInfo, destructive []DisplayRow) bool {
+	input = strings.TrimSpace(input)
+
+	return input == info.StackName || input == strconv.Itoa(len(destructive))
+}
+
+//GuardAction determines what happens when a guard rule matches a stack that's being deleted
+type GuardAction string
+
+const (
+	guardFile string = "guard.json"
+
+	//GuardActionConfirm requires the stack name typed before deleting the stack
+	GuardActionConfirm GuardAction = "confirm"
+
+	//GuardActionRefuse refuses to delete the stack
+	GuardActionRefuse GuardAction = "refuse"
+)
+
+//GuardRule matches stacks by name pattern and tags. A rule without names matches any name, and a rule without tags matches any tags
+type GuardRule struct {
+	Names  []string          `json:"names"`
+	Tags   map[string]string `json:"tags"`
+	Action GuardAction       `json:"action"`
+}
+
+//GuardPolicy is a list of rules that protect stacks from deletion
+type GuardPolicy struct {
+	Rules []GuardRule `json:"rules"`
+}
+
+//GetGuardPolicy loads a guard policy from the given location, or from the user's config directory if no location is given. If no policy exists,
+//return an empty policy
+func GetGuardPolicy(location string) (GuardPolicy, error) {
+	var policy GuardPolicy
+
+	if location == "" {
+		var err error
+
+		location, err = ConfigLocation(guardFile)
+		if err != nil {
+			return policy, nil
+		}
+	}
+
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return policy, nil
+	}
+
+	if err := json.Unmarshal(contents, &policy); err != nil {
+		return policy, errors.New(colors.Error(fmt.Sprintf("Unable to load guard policy %s. %s", location, err)))
+	}
+
+	for _, rule := range policy.Rules {
+		if rule.Action != GuardActionConfirm && rule.Action != GuardActionRefuse {
+			return policy, errors.New(colors.Error(fmt.Sprintf("Unable to load guard policy %s. Rule action must be confirm or refuse", location)))
+		}
+	}
+
+	return policy, nil
+}
+
+func (rule GuardRule) matchesName(stackName string) bool {
+	if len(rule.Names) == 0 {
+		return true
+	}
+
+	for _, pattern := range rule.Names {
+		if matched, _ := path.Match(pattern, stackName); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rule GuardRule) matchesTags(tags []cloudformation.Tag) bool {
+	for key, value := range rule.Tags {
+		found := false
+
+		for _, tag := range tags {
+			if *tag.Key == key && *tag.Value == value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+//String describes what the rule matches
+func (rule GuardRule) String() string {
+	conditions := make([]string, 0)
+
+	if len(rule.Names) > 0 {
+		conditions = append(conditions, "name "+strings.Join(rule.Names, " or "))
+	}
+
+	keys := make([]string, 0, len(rule.Tags))
+	for key := range rule.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		conditions = append(conditions, "tag "+key+"="+rule.Tags[key])
+	}
+
+	if len(conditions) == 0 {
+		return "every stack"
+	}
+
+	return strings.Join(conditions, " and ")
+}
+
+//Evaluate returns the strictest rule that matches the stack. Refusing rules take precedence over confirming rules
+func (policy GuardPolicy) Evaluate(stackName string, tags []cloudformation.Tag) (GuardRule, bool) {
+	var matched GuardRule
+	found := false
+
+	for _, rule := range policy.Rules {
+		if !rule.matchesName(stackName) || !rule.matchesTags(tags) {
+			continue
+		}
+
+		if !found || rule.Action == GuardActionRefuse {
+			matched = rule
+			found = true
+		}
+
+		if rule.Action == GuardActionRefuse {
+			break
+		}
+	}
+
+	return matched, found
+}
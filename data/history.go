@@ -0,0 +1,171 @@
+package data
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/utils"
+)
+
+const historyFile string = "history.json"
+
+//DurationRecord is the running average of how long a resource type takes to reach a terminal status
+type DurationRecord struct {
+	Count   int
+	Average time.Duration
+}
+
+//DurationHistory stores duration records of previous deployments keyed by resource type and terminal status
+type DurationHistory map[string]DurationRecord
+
+//Progress is a count of display rows in each stage of a stack operation
+type Progress struct {
+	Total      int
+	Complete   int
+	InProgress int
+	Failed     int
+}
+
+var expectedStatus map[cloudformation.ChangeAction]cloudformation.ResourceStatus = map[cloudformation.ChangeAction]cloudformation.ResourceStatus{
+	cloudformation.ChangeActionAdd:    cloudformation.ResourceStatusCreateComplete,
+	cloudformation.ChangeActionModify: cloudformation.ResourceStatusUpdateComplete,
+	cloudformation.ChangeActionRemove: cloudformation.ResourceStatusDeleteComplete,
+}
+
+func historyKey(resourceType string, status cloudformation.ResourceStatus) string {
+	return resourceType + "/" + string(status)
+}
+
+//ConfigLocation returns the location of a cirrus file in the user's config directory
+func ConfigLocation(file string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cirrus", file), nil
+}
+
+//GetDurationHistory loads the duration history from the user's config directory. If no history exists, return an empty history
+func GetDurationHistory() DurationHistory {
+	history := make(DurationHistory)
+
+	location, err := ConfigLocation(historyFile)
+	if err != nil {
+		return history
+	}
+
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return history
+	}
+
+	if err := json.Unmarshal(contents, &history); err != nil {
+		return make(DurationHistory)
+	}
+
+	return history
+}
+
+//RecordDurations adds every successfully completed resource timing to the history and saves it to the user's config directory
+func RecordDurations(history DurationHistory, timings map[string]ResourceTiming) error {
+	for _, timing := range timings {
+		if !timing.Complete() || !utils.ContainsResourceStatus(PositiveEventStatus, timing.Status) {
+			continue
+		}
+
+		key := historyKey(timing.ResourceType, timing.Status)
+		record := history[key]
+
+		total := record.Average*time.Duration(record.Count) + timing.Duration(timing.End)
+		record.Count++
+		record.Average = total / time.Duration(record.Count)
+
+		history[key] = record
+	}
+
+	location, err := ConfigLocation(historyFile)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(location), 0755); err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(location, contents, 0644)
+}
+
+//ExpectedStatus returns the terminal status a display row is working towards
+func ExpectedStatus(row DisplayRow) cloudformation.ResourceStatus {
+	if row.Source == DisplayRowSourceEvent {
+		return cloudformation.ResourceStatus(strings.Replace(string(row.Status), "IN_PROGRESS", "COMPLETE", 1))
+	}
+
+	return expectedStatus[row.Action]
+}
+
+//GetProgress counts the display rows that are complete, in progress and failed
+func GetProgress(displayRows map[string]DisplayRow) Progress {
+	progress := Progress{Total: len(displayRows)}
+
+	for _, row := range displayRows {
+		if row.Source != DisplayRowSourceEvent {
+			continue
+		}
+
+		switch {
+		case utils.ContainsResourceStatus(PendingEventStatus, row.Status):
+			progress.InProgress++
+		case utils.ContainsResourceStatus(NegativeEventStatus, row.Status):
+			progress.Failed++
+		default:
+			progress.Complete++
+		}
+	}
+
+	return progress
+}
+
+//EstimateRemaining estimates the time until all display rows reach a terminal status using the duration history. Resources that have not started
+//are assumed to start once the slowest in progress resource finishes. The estimate is unknown (false) if any outstanding resource type has no history
+func EstimateRemaining(history DurationHistory, displayRows map[string]DisplayRow, timings map[string]ResourceTiming, now time.Time) (time.Duration, bool) {
+	var inProgress, pending time.Duration
+
+	for logicalID, row := range displayRows {
+		if row.Source == DisplayRowSourceEvent && !utils.ContainsResourceStatus(PendingEventStatus, row.Status) {
+			continue
+		}
+
+		record, exists := history[historyKey(row.ResourceType, ExpectedStatus(row))]
+		if !exists {
+			return 0, false
+		}
+
+		timing, started := timings[logicalID]
+		if !started || timing.Complete() {
+			if record.Average > pending {
+				pending = record.Average
+			}
+
+			continue
+		}
+
+		remaining := record.Average - timing.Duration(now)
+		if remaining > inProgress {
+			inProgress = remaining
+		}
+	}
+
+	return inProgress + pending, true
+}
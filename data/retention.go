@@ -0,0 +1,96 @@
+package data
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/template"
+)
+
+//DeletionPolicy is the CloudFormation DeletionPolicy attribute of a resource
+type DeletionPolicy string
+
+const (
+	//DeletionPolicyDelete deletes the resource along with the stack. It's the default for most resources
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	//DeletionPolicyRetain leaves the resource behind when it's removed from the stack
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+
+	//DeletionPolicySnapshot snapshots the resource before deleting it
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
+//RetainedResource is a resource that's left behind or snapshotted when its stack is deleted
+type RetainedResource struct {
+	LogicalResourceID  string
+	PhysicalResourceID string
+	ResourceType       string
+	DeletionPolicy     DeletionPolicy
+}
+
+//GetDeletionPolicies parses a JSON or YAML template and returns the deletion policy of every resource that sets one. Policies set with intrinsic
+//functions are skipped since they can't be resolved from the template alone
+func GetDeletionPolicies(contents []byte) (map[string]DeletionPolicy, error) {
+	parsed, err := template.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]DeletionPolicy)
+
+	for _, resource := range parsed.Resources {
+		if policy, ok := resource.DeletionPolicy.Literal(); ok {
+			policies[resource.LogicalID] = DeletionPolicy(policy)
+		}
+	}
+
+	return policies, nil
+}
+
+//IsRetained determines if a display row's resource outlives its removal, either left behind or as a snapshot
+func IsRetained(row DisplayRow) bool {
+	return row.DeletionPolicy == DeletionPolicyRetain || row.DeletionPolicy == DeletionPolicySnapshot
+}
+
+//AnnotateDeletionPolicies returns the display rows with their deletion policies set, without side-effects
+func AnnotateDeletionPolicies(displayRows map[string]DisplayRow, policies map[string]DeletionPolicy) map[string]DisplayRow {
+	annotated := make(map[string]DisplayRow)
+
+	for logicalID, row := range displayRows {
+		row.DeletionPolicy = policies[logicalID]
+		annotated[logicalID] = row
+	}
+
+	return annotated
+}
+
+//RetainedResources returns the resources a stack deletion leaves behind or snapshots, ordered by logical ID
+func RetainedResources(resources []cloudformation.StackResourceSummary, policies map[string]DeletionPolicy) []RetainedResource {
+	retained := make([]RetainedResource, 0)
+
+	for _, resource := range resources {
+		policy := policies[*resource.LogicalResourceId]
+		if policy != DeletionPolicyRetain && policy != DeletionPolicySnapshot {
+			continue
+		}
+
+		var physicalID string
+		if resource.PhysicalResourceId != nil {
+			physicalID = *resource.PhysicalResourceId
+		}
+
+		retained = append(retained, RetainedResource{
+			LogicalResourceID:  *resource.LogicalResourceId,
+			PhysicalResourceID: physicalID,
+			ResourceType:       *resource.ResourceType,
+			DeletionPolicy:     policy,
+		})
+	}
+
+	sort.Slice(retained, func(i, j int) bool {
+		return retained[i].LogicalResourceID < retained[j].LogicalResourceID
+	})
+
+	return retained
+}
@@ -0,0 +1,121 @@
+package data
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/utils"
+)
+
+//ResourceTiming tracks when a resource started and finished deploying
+type ResourceTiming struct {
+	LogicalResourceID string
+	ResourceType      string
+	Status            cloudformation.ResourceStatus
+	Start             time.Time
+	End               time.Time
+}
+
+//Complete determines if the resource has reached a terminal state
+func (timing ResourceTiming) Complete() bool {
+	return !timing.End.IsZero()
+}
+
+//Duration returns how long the resource took to deploy. Resources still in progress are measured against now
+func (timing ResourceTiming) Duration(now time.Time) time.Duration {
+	if timing.Complete() {
+		return timing.End.Sub(timing.Start)
+	}
+
+	return now.Sub(timing.Start)
+}
+
+//TrackTiming records an event against the resource timings. The first in progress event starts the clock and the latest terminal event stops it
+func TrackTiming(timings map[string]ResourceTiming, event cloudformation.StackEvent) {
+	timing, exists := timings[*event.LogicalResourceId]
+
+	if !exists {
+		timing = ResourceTiming{
+			LogicalResourceID: *event.LogicalResourceId,
+			ResourceType:      *event.ResourceType,
+			Start:             *event.Timestamp,
+		}
+	}
+
+	timing.Status = event.ResourceStatus
+
+	if utils.ContainsResourceStatus(PendingEventStatus, event.ResourceStatus) {
+		timing.End = time.Time{}
+	} else {
+		timing.End = *event.Timestamp
+	}
+
+	timings[*event.LogicalResourceId] = timing
+}
+
+//SortTimings returns the resource timings ordered by start time
+func SortTimings(timings map[string]ResourceTiming) []ResourceTiming {
+	sorted := make([]ResourceTiming, 0)
+
+	for _, timing := range timings {
+		sorted = append(sorted, timing)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Start.Equal(sorted[j].Start) {
+			return sorted[i].LogicalResourceID < sorted[j].LogicalResourceID
+		}
+
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	return sorted
+}
+
+//LongestRunning returns up to count resource timings ordered from longest to shortest
+func LongestRunning(timings map[string]ResourceTiming, count int, now time.Time) []ResourceTiming {
+	sorted := SortTimings(timings)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration(now) > sorted[j].Duration(now)
+	})
+
+	if len(sorted) > count {
+		sorted = sorted[:count]
+	}
+
+	return sorted
+}
+
+//CriticalPath estimates the chain of resources that determined the total deployment time. Starting at the last resource to finish, it walks
+//backwards to the resource that finished most recently before each one started. CloudFormation starts a resource as soon as its dependencies complete,
+//so this chain approximates the dependency path that dominated the deployment
+func CriticalPath(timings map[string]ResourceTiming) []ResourceTiming {
+	path := make([]ResourceTiming, 0)
+	sorted := SortTimings(timings)
+
+	var current *ResourceTiming
+	for i := range sorted {
+		if sorted[i].Complete() && (current == nil || sorted[i].End.After(current.End)) {
+			current = &sorted[i]
+		}
+	}
+
+	for current != nil {
+		path = append([]ResourceTiming{*current}, path...)
+
+		var previous *ResourceTiming
+		for i := range sorted {
+			timing := &sorted[i]
+
+			if timing.Complete() && !timing.End.After(current.Start) && timing.End.Before(current.End) && (previous == nil || timing.End.After(previous.End)) {
+				previous = timing
+			}
+		}
+
+		current = previous
+	}
+
+	return path
+}
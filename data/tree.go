@@ -0,0 +1,79 @@
+package data
+
+import (
+	"strings"
+
+	"github.com/blueseph/cirrus/utils"
+)
+
+//TreeRow is a display row positioned in the nested stack tree
+type TreeRow struct {
+	DisplayRow
+	Depth       int
+	Descendants int
+	Failed      int
+	Collapsed   bool
+}
+
+//Name returns the logical ID of the row without its nested stack ancestors
+func (row TreeRow) Name() string {
+	return row.LogicalResourceID[strings.LastIndex(row.LogicalResourceID, NestedStackSeparator)+1:]
+}
+
+//FlattenDisplayRows orders the display rows as a tree, placing nested stack resources beneath their nested stack. Rows are filtered and sorted with
+//the display options. Nested stacks stay visible while any of their resources match, and the resources of collapsed nested stacks are omitted
+func FlattenDisplayRows(displayRows map[string]DisplayRow, options DisplayOptions) []TreeRow {
+	children := make(map[string][]DisplayRow)
+
+	for _, row := range displayRows {
+		children[row.Parent] = append(children[row.Parent], row)
+	}
+
+	return flattenChildren(children, "", 0, options)
+}
+
+func flattenChildren(children map[string][]DisplayRow, parent string, depth int, options DisplayOptions) []TreeRow {
+	rows := make([]TreeRow, 0)
+
+	for _, row := range SortDisplayRows(children[parent], options.Sort) {
+		descendants := flattenChildren(children, row.LogicalResourceID, depth+1, options)
+
+		if !MatchesDisplayOptions(row, options) && len(descendants) == 0 {
+			continue
+		}
+
+		treeRow := TreeRow{
+			DisplayRow: row,
+			Depth:      depth,
+			Collapsed:  options.Collapsed[row.LogicalResourceID],
+		}
+
+		treeRow.Descendants, treeRow.Failed = countDescendants(children, row.LogicalResourceID)
+
+		rows = append(rows, treeRow)
+
+		if !treeRow.Collapsed {
+			rows = append(rows, descendants...)
+		}
+	}
+
+	return rows
+}
+
+func countDescendants(children map[string][]DisplayRow, parent string) (int, int) {
+	var total, failed int
+
+	for _, row := range children[parent] {
+		total++
+
+		if row.Source == DisplayRowSourceEvent && utils.ContainsResourceStatus(NegativeEventStatus, row.Status) {
+			failed++
+		}
+
+		descendants, failedDescendants := countDescendants(children, row.LogicalResourceID)
+		total += descendants
+		failed += failedDescendants
+	}
+
+	return total, failed
+}
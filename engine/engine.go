@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/utils"
+)
+
+var (
+	//ErrDeclined is returned when the renderer declines the change set or stack deletion
+	ErrDeclined = errors.New("user declined the operation")
+
+	//ErrOperationFailed is returned when the stack operation fails
+	ErrOperationFailed = errors.New("operation failed")
+
+	//ErrDetached is returned when the context is cancelled before the stack operation finishes. The operation continues in CloudFormation
+	ErrDetached = errors.New("stopped watching the stack operation")
+
+	pollInterval = 500 * time.Millisecond
+)
+
+//Renderer presents the stack operation lifecycle. Confirm is called once with the changes to review, then Render is called from a single goroutine
+//for every event until the stack reaches a terminal status. An error returned from Render stops the lifecycle
+type Renderer interface {
+	Confirm(event ChangeSetReady) (bool, error)
+	Render(event Event) error
+}
+
+//RunChangeSet presents the change set and its nested change sets to the renderer and executes it once confirmed, rendering events until the stack
+//reaches a terminal status. The options determine which changes are destructive, which stack policies apply, the template's security findings and
+//the environment being deployed
+func RunChangeSet(ctx context.Context, renderer Renderer, info data.StackInfo, changeSet *cloudformation.DescribeChangeSetResponse, nestedChanges map[string][]cloudformation.Change, operation cfn.StackOperation, options ChangeSetOptions) error {
+	displayRows := data.ChangeMap(changeSet.Changes, false)
+	for key, row := range data.NestedChangeMap(nestedChanges, false) {
+		displayRows[key] = row
+	}
+
+	return run(ctx, renderer, ChangeSetReady{
+		Info:          info,
+		Operation:     operation,
+		DisplayRows:   displayRows,
+		Changes:       changeSet.Changes,
+		NestedChanges: nestedChanges,
+		Destructive:   data.DestructiveRows(displayRows, options.StatefulTypes),
+		Security:      options.Security,
+		Environment:   options.Environment,
+	}, nil, options)
+}
+
+//RunDelete presents the stack resources, annotated with their deletion policies, to the renderer and deletes the stack once confirmed, rendering
+//events until the stack is deleted. Resources of the stateful types that aren't retained are flagged as destructive, and the result lists the
+//retained resources. A guarded stack, described by the guard rule it matched, needs its name typed
+func RunDelete(ctx context.Context, renderer Renderer, info data.StackInfo, resources []cloudformation.StackResourceSummary, policies map[string]data.DeletionPolicy, statefulTypes []string, guarded string) error {
+	displayRows := data.AnnotateDeletionPolicies(data.ResourceMap(resources), policies)
+
+	return run(ctx, renderer, ChangeSetReady{
+		Info:        info,
+		Operation:   cfn.StackOperationDelete,
+		DisplayRows: displayRows,
+		Destructive: data.DestructiveRows(displayRows, statefulTypes),
+		Guarded:     guarded,
+	}, data.RetainedResources(resources, policies), ChangeSetOptions{})
+}
+
+func run(ctx context.Context, renderer Renderer, ready ChangeSetReady, retained []data.RetainedResource, options ChangeSetOptions) error {
+	confirmed, err := renderer.Confirm(ready)
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return ErrDeclined
+	}
+
+	policy, err := applyStackPolicy(ready.Info, ready.Operation, options)
+	if err != nil {
+		return err
+	}
+
+	err = executeOperation(ready.Operation, ready.Info)
+	if err == nil {
+		err = tail(ctx, renderer, ready.Info, ready.Operation, data.ActivateDisplayRows(ready.DisplayRows), retained)
+	}
+
+	return finishStackPolicy(ready.Info, ready.Operation, policy, err)
+}
+
+func executeOperation(operation cfn.StackOperation, info data.StackInfo) error {
+	if operation == cfn.StackOperationDelete {
+		return cfn.DeleteStack(info)
+	}
+
+	return cfn.ExecuteChangeSet(info)
+}
+
+//failed determines if a terminal stack status, along with the resource failures seen on the way, means the operation failed
+func failed(status cloudformation.ResourceStatus, failures []cloudformation.StackEvent) bool {
+	return len(failures) > 0 || utils.ContainsStackStatus(data.NegativeStackStatus, status) || string(status) == string(cloudformation.StackStatusRollbackComplete)
+}
+
+//getOutputs returns the stack outputs once an operation completes. Deleted stacks have none
+func getOutputs(info data.StackInfo, operation cfn.StackOperation) []cloudformation.Output {
+	if operation == cfn.StackOperationDelete {
+		return nil
+	}
+
+	stack, err := cfn.GetStack(info.StackID)
+	if err != nil || len(stack.Stacks) == 0 {
+		return nil
+	}
+
+	return stack.Stacks[0].Outputs
+}
+
+func tail(ctx context.Context, renderer Renderer, info data.StackInfo, operation cfn.StackOperation, displayRows map[string]data.DisplayRow, retained []data.RetainedResource) error {
+	start := time.Now()
+	tailer := cfn.NewEventTailer(info)
+	history := data.GetDurationHistory()
+
+	timings := make(map[string]data.ResourceTiming)
+	failures := make([]cloudformation.StackEvent, 0)
+	events := make([]cloudformation.StackEvent, 0)
+
+	for {
+		for _, event := range tailer.Poll() {
+			events = append(events, event.StackEvent)
+
+			if !event.Root {
+				row := data.CreateDisplayRowFromEvent(event.StackEvent)
+				row.Action = displayRows[row.LogicalResourceID].Action
+				row.DeletionPolicy = displayRows[row.LogicalResourceID].DeletionPolicy
+				row.Parent = event.Parent
+
+				displayRows[row.LogicalResourceID] = row
+				data.TrackTiming(timings, event.StackEvent)
+
+				if utils.ContainsResourceStatus(data.NegativeEventStatus, event.ResourceStatus) {
+					failures = append(failures, event.StackEvent)
+				}
+
+				err := renderer.Render(ResourceUpdated{
+					Event:  event.StackEvent,
+					Row:    row,
+					Timing: timings[row.LogicalResourceID],
+				})
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if utils.ContainsStackStatus(data.PendingStackStatus, event.ResourceStatus) {
+				err := renderer.Render(StackUpdated{Event: event.StackEvent})
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			// duration history only improves estimates, a failure to save it shouldn't fail the operation
+			_ = data.RecordDurations(history, timings)
+
+			result := StackResult{
+				Info:     info,
+				Status:   cloudformation.StackStatus(event.ResourceStatus),
+				Duration: time.Since(start),
+				Timings:  timings,
+				Events:   events,
+				Outputs:  getOutputs(info, operation),
+				Retained: retained,
+			}
+
+			if failed(event.ResourceStatus, failures) {
+				err := renderer.Render(StackFailed{StackResult: result, Failures: failures})
+				if err != nil {
+					return err
+				}
+
+				return ErrOperationFailed
+			}
+
+			return renderer.Render(StackCompleted{StackResult: result})
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrDetached
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/security"
+)
+
+//EventType names a lifecycle event
+type EventType string
+
+const (
+	//EventTypeChangeSetReady is the type of ChangeSetReady events
+	EventTypeChangeSetReady EventType = "ChangeSetReady"
+
+	//EventTypeResourceUpdated is the type of ResourceUpdated events
+	EventTypeResourceUpdated EventType = "ResourceUpdated"
+
+	//EventTypeStackUpdated is the type of StackUpdated events
+	EventTypeStackUpdated EventType = "StackUpdated"
+
+	//EventTypeStackCompleted is the type of StackCompleted events
+	EventTypeStackCompleted EventType = "StackCompleted"
+
+	//EventTypeStackFailed is the type of StackFailed events
+	EventTypeStackFailed EventType = "StackFailed"
+)
+
+//Event is a lifecycle event emitted to a renderer
+type Event interface {
+	Type() EventType
+}
+
+//ChangeSetReady is emitted once the changes are ready for review. For deletes, the display rows are the stack's resources and there are no changes.
+//Destructive changes remove or replace stateful resources, and guarded stacks match a guard policy rule. Renderers require a typed confirmation for
+//either. Security findings describe the IAM and network exposure the template adds. The environment is set when deploying to one, with the
+//effective parameters and tags and the files they come from
+type ChangeSetReady struct {
+	Info          data.StackInfo
+	Operation     cfn.StackOperation
+	DisplayRows   map[string]data.DisplayRow
+	Changes       []cloudformation.Change
+	NestedChanges map[string][]cloudformation.Change
+	Destructive   []data.DisplayRow
+	Guarded       string
+	Security      []security.Finding
+	Environment   *data.Environment
+}
+
+//ResourceUpdated is emitted whenever a resource, including resources of nested stacks, changes status
+type ResourceUpdated struct {
+	Event  cloudformation.StackEvent
+	Row    data.DisplayRow
+	Timing data.ResourceTiming
+}
+
+//StackUpdated is emitted whenever the stack moves to another non-terminal status, such as when it starts rolling back
+type StackUpdated struct {
+	Event cloudformation.StackEvent
+}
+
+//StackResult describes a stack that reached a terminal status
+type StackResult struct {
+	Info     data.StackInfo
+	Status   cloudformation.StackStatus
+	Duration time.Duration
+	Timings  map[string]data.ResourceTiming
+	Events   []cloudformation.StackEvent
+	Outputs  []cloudformation.Output
+	Retained []data.RetainedResource
+}
+
+//StackCompleted is emitted when the stack operation succeeds
+type StackCompleted struct {
+	StackResult
+}
+
+//StackFailed is emitted when the stack operation fails, along with the resource failures that caused it
+type StackFailed struct {
+	StackResult
+	Failures []cloudformation.StackEvent
+}
+
+//Type returns the event's type
+func (event ChangeSetReady) Type() EventType {
+	return EventTypeChangeSetReady
+}
+
+//RequiresTypedConfirmation determines if the operation needs a typed confirmation rather than a button press or Y/N answer
+func (event ChangeSetReady) RequiresTypedConfirmation() bool {
+	return len(event.Destructive) > 0 || event.Guarded != ""
+}
+
+//ConfirmedBy determines if a typed confirmation confirms the operation. Guarded stacks need their name typed, destructive changes also accept
+//the number of destructive changes
+func (event ChangeSetReady) ConfirmedBy(input string) bool {
+	if event.Guarded != "" {
+		return strings.TrimSpace(input) == event.Info.StackName
+	}
+
+	return data.ConfirmsDestructive(input, event.Info, event.Destructive)
+}
+
+//Type returns the event's type
+func (event ResourceUpdated) Type() EventType {
+	return EventTypeResourceUpdated
+}
+
+//Type returns the event's type
+func (event StackUpdated) Type() EventType {
+	return EventTypeStackUpdated
+}
+
+//Type returns the event's type
+func (event StackCompleted) Type() EventType {
+	return EventTypeStackCompleted
+}
+
+//Type returns the event's type
+func (event StackFailed) Type() EventType {
+	return EventTypeStackFailed
+}
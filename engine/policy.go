@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/security"
+)
+
+//allowAllPolicy is equivalent to a stack without a stack policy, which can't be removed once set
+var allowAllPolicy []byte = []byte(`{"Statement":[{"Effect":"Allow","Action":"Update:*","Principal":"*","Resource":"*"}]}`)
+
+//ChangeSetOptions determines how a change set is guarded and applied. Removing or replacing resources of the stateful types is destructive. The
+//stack policy is set before an update, or once the stack is created. The override policy replaces the stack policy during an update only, after
+//which the stack policy, or the previous policy without one, is restored. Security findings of the template, and the effective parameters and
+//tags of the environment being deployed, are shown along with the changes
+type ChangeSetOptions struct {
+	StatefulTypes  []string
+	StackPolicy    []byte
+	OverridePolicy []byte
+	Security       []security.Finding
+	Environment    *data.Environment
+}
+
+//applyStackPolicy sets the policy needed before the operation executes and returns the policy to set once it finishes, if any
+func applyStackPolicy(info data.StackInfo, operation cfn.StackOperation, options ChangeSetOptions) ([]byte, error) {
+	switch {
+	case operation == cfn.StackOperationDelete:
+		return nil, nil
+	case operation == cfn.StackOperationCreate:
+		return options.StackPolicy, nil
+	case options.OverridePolicy == nil:
+		if options.StackPolicy == nil {
+			return nil, nil
+		}
+
+		return nil, cfn.SetStackPolicy(info, options.StackPolicy)
+	}
+
+	restore := options.StackPolicy
+
+	if restore == nil {
+		previous, err := cfn.GetStackPolicy(info)
+		if err != nil {
+			return nil, err
+		}
+
+		restore = previous
+		if len(previous) == 0 {
+			restore = allowAllPolicy
+		}
+	}
+
+	return restore, cfn.SetStackPolicy(info, options.OverridePolicy)
+}
+
+//PendingPolicyError is returned when watching stops before the operation finishes, so the policy that's set once it finishes isn't. Setting it
+//while the operation runs would end an override policy early, so it's left to the user
+type PendingPolicyError struct {
+	Info   data.StackInfo
+	Policy []byte
+}
+
+func (err PendingPolicyError) Error() string {
+	return fmt.Sprintf("stack policy of %s wasn't set after the operation", err.Info.StackName)
+}
+
+//Unwrap returns ErrDetached, since the operation continues in CloudFormation
+func (err PendingPolicyError) Unwrap() error {
+	return ErrDetached
+}
+
+//finishStackPolicy sets the policy once the operation finishes. A stack that failed to create has no policy to set, and a detached operation
+//hasn't finished, so its policy is pending
+func finishStackPolicy(info data.StackInfo, operation cfn.StackOperation, policy []byte, result error) error {
+	if policy == nil {
+		return result
+	}
+
+	if errors.Is(result, ErrDetached) {
+		return PendingPolicyError{Info: info, Policy: policy}
+	}
+
+	if operation == cfn.StackOperationCreate && result != nil {
+		return result
+	}
+
+	err := cfn.SetStackPolicy(info, policy)
+	if result != nil {
+		return result
+	}
+
+	return err
+}
@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+//ErrCfnLintMissing is returned when cfn-lint isn't on the PATH
+var ErrCfnLintMissing = errors.New("cfn-lint isn't installed")
+
+//cfnLintFinding is a finding in cfn-lint's JSON output
+type cfnLintFinding struct {
+	Level    string `json:"Level"`
+	Message  string `json:"Message"`
+	Location struct {
+		Path  []interface{} `json:"Path"`
+		Start struct {
+			LineNumber   int `json:"LineNumber"`
+			ColumnNumber int `json:"ColumnNumber"`
+		} `json:"Start"`
+	} `json:"Location"`
+	Rule struct {
+		ID string `json:"Id"`
+	} `json:"Rule"`
+}
+
+//RunCfnLint runs cfn-lint against the template at the given location and returns its findings. cfn-lint exits non-zero when it finds anything,
+//so only output that isn't a JSON list of findings is an error
+func RunCfnLint(location string) ([]Finding, error) {
+	path, err := exec.LookPath("cfn-lint")
+	if err != nil {
+		return nil, ErrCfnLintMissing
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	command := exec.Command(path, "--format", "json", "--", location)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	runErr := command.Run()
+
+	var results []cfnLintFinding
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		if runErr == nil {
+			runErr = err
+		}
+
+		return nil, fmt.Errorf("cfn-lint failed: %s %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+
+	findings := make([]Finding, 0, len(results))
+
+	for _, result := range results {
+		path := make([]string, 0, len(result.Location.Path))
+		for _, segment := range result.Location.Path {
+			path = append(path, fmt.Sprint(segment))
+		}
+
+		findings = append(findings, Finding{
+			RuleID:   result.Rule.ID,
+			Severity: Severity(result.Level),
+			Message:  result.Message,
+			Path:     path,
+			Line:     result.Location.Start.LineNumber,
+			Column:   result.Location.Start.ColumnNumber,
+			Source:   SourceCfnLint,
+		})
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,148 @@
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/template"
+)
+
+//reasonNotGiven is shown for suppressions without a reason
+const reasonNotGiven string = "no reason given"
+
+//RuleConfig changes how a rule's findings are reported. A disabled rule's findings are suppressed, with the reason shown alongside them
+type RuleConfig struct {
+	Enabled  *bool    `json:"enabled"`
+	Severity Severity `json:"severity"`
+	Reason   string   `json:"reason"`
+}
+
+//Config is a project's lint configuration, keyed by rule ID. It applies to cfn-lint's rules as well as the built-in rules
+type Config struct {
+	Rules map[string]RuleConfig `json:"rules"`
+}
+
+//Suppression is a finding that was suppressed by the lint configuration or the template, kept so suppressions can be audited
+type Suppression struct {
+	Finding
+	Reason string
+}
+
+//Result is the outcome of linting a template
+type Result struct {
+	Findings   []Finding
+	Suppressed []Suppression
+}
+
+//templateSuppression suppresses a rule for a resource, set in its Metadata under cirrus.lint.suppress
+type templateSuppression struct {
+	Rule   string `yaml:"Rule"`
+	Reason string `yaml:"Reason"`
+}
+
+//GetConfig loads the lint configuration at the given location. A missing file is an empty configuration
+func GetConfig(location string) (Config, error) {
+	var config Config
+
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return config, nil
+	}
+
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return config, errors.New(colors.Error(fmt.Sprintf("Unable to load lint configuration %s. %s", location, err)))
+	}
+
+	for ruleID, rule := range config.Rules {
+		if rule.Severity != "" && rule.Severity != SeverityError && rule.Severity != SeverityWarning && rule.Severity != SeverityInformational {
+			return config, errors.New(colors.Error(fmt.Sprintf("Unable to load lint configuration %s. Severity of %s must be Error, Warning or Informational", location, ruleID)))
+		}
+	}
+
+	return config, nil
+}
+
+//Apply applies the configuration and the template's suppressions to the findings. Severities are overridden before findings are suppressed
+func (config Config) Apply(contents []byte, findings []Finding) Result {
+	result := Result{
+		Findings:   make([]Finding, 0, len(findings)),
+		Suppressed: make([]Suppression, 0),
+	}
+
+	suppressions := getTemplateSuppressions(contents)
+
+	for _, finding := range findings {
+		rule, configured := config.Rules[finding.RuleID]
+
+		if configured && rule.Severity != "" {
+			finding.Severity = rule.Severity
+		}
+
+		if configured && rule.Enabled != nil && !*rule.Enabled {
+			result.Suppressed = append(result.Suppressed, Suppression{Finding: finding, Reason: reasonOrDefault(rule.Reason)})
+			continue
+		}
+
+		if reason, ok := suppressed(finding, suppressions); ok {
+			result.Suppressed = append(result.Suppressed, Suppression{Finding: finding, Reason: reason})
+			continue
+		}
+
+		result.Findings = append(result.Findings, finding)
+	}
+
+	return result
+}
+
+func reasonOrDefault(reason string) string {
+	if reason == "" {
+		return reasonNotGiven
+	}
+
+	return reason
+}
+
+//getTemplateSuppressions returns the suppressions of each resource. A template that can't be parsed has none, since its parse error is already a
+//finding
+func getTemplateSuppressions(contents []byte) map[string][]templateSuppression {
+	found := make(map[string][]templateSuppression)
+
+	parsed, err := template.Parse(contents)
+	if err != nil {
+		return found
+	}
+
+	for _, resource := range parsed.Resources {
+		suppress := resource.Metadata.Field("cirrus").Field("lint").Field("suppress")
+		if !suppress.IsSet() {
+			continue
+		}
+
+		var suppressions []templateSuppression
+		if err := suppress.Node().Decode(&suppressions); err != nil {
+			continue
+		}
+
+		found[resource.LogicalID] = append(found[resource.LogicalID], suppressions...)
+	}
+
+	return found
+}
+
+//suppressed determines if a finding is within a resource that suppresses its rule, and returns the reason
+func suppressed(finding Finding, suppressions map[string][]templateSuppression) (string, bool) {
+	if len(finding.Path) < 2 || finding.Path[0] != sectionResources {
+		return "", false
+	}
+
+	for _, suppression := range suppressions[finding.Path[1]] {
+		if suppression.Rule == finding.RuleID {
+			return reasonOrDefault(suppression.Reason), true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"sort"
+)
+
+//Severity is how serious a finding is. Errors block a deployment, warnings need confirmation
+type Severity string
+
+const (
+	//SeverityError is a problem that will fail the deployment
+	SeverityError Severity = "Error"
+
+	//SeverityWarning is a likely problem or a deviation from best practices
+	SeverityWarning Severity = "Warning"
+
+	//SeverityInformational is a suggestion
+	SeverityInformational Severity = "Informational"
+)
+
+//Severities is the order severities are shown in
+var Severities []Severity = []Severity{
+	SeverityError,
+	SeverityWarning,
+	SeverityInformational,
+}
+
+//Finding is a problem found in a template
+type Finding struct {
+	RuleID   string
+	Severity Severity
+	Message  string
+	Path     []string
+	Line     int
+	Column   int
+	Source   string
+}
+
+//GroupBySeverity returns the findings of each severity, ordered by line
+func GroupBySeverity(findings []Finding) map[Severity][]Finding {
+	groups := make(map[Severity][]Finding)
+
+	for _, finding := range findings {
+		groups[finding.Severity] = append(groups[finding.Severity], finding)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Line < group[j].Line
+		})
+	}
+
+	return groups
+}
+
+//HasSeverity determines if any finding has the given severity
+func HasSeverity(findings []Finding, severity Severity) bool {
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,360 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blueseph/cirrus/spec"
+	"github.com/blueseph/cirrus/template"
+)
+
+//propertyProblem is the kind of problem property validation finds. Each kind is reported by its own rule
+type propertyProblem int
+
+const (
+	problemUnknown propertyProblem = iota
+	problemRequired
+	problemType
+	problemAllowedValue
+	problemPattern
+)
+
+//maxSuggestionDistance is how different an unknown property can be from a known one for it to be suggested
+const maxSuggestionDistance int = 3
+
+//propertyValidator validates the properties of a resource against the resource specification, finding problems of a single kind
+type propertyValidator struct {
+	specification *spec.Specification
+	resourceType  string
+	problem       propertyProblem
+	findings      []Finding
+}
+
+func (v *propertyValidator) report(problem propertyProblem, position template.Position, path []string, format string, args ...interface{}) {
+	if problem == v.problem {
+		v.findings = append(v.findings, newFinding(position, path, format, args...))
+	}
+}
+
+//checkProperties validates every resource of a type in the specification. Resource types it doesn't cover, such as custom resources, are skipped.
+//Intrinsic functions can't be known before deploying, so they aren't validated
+func checkProperties(t *Template, problem propertyProblem) []Finding {
+	if t.specification == nil {
+		return nil
+	}
+
+	findings := make([]Finding, 0)
+
+	for _, resource := range t.Resources {
+		definition, ok := t.specification.ResourceTypes[resource.Type]
+		if !ok {
+			continue
+		}
+
+		validator := propertyValidator{
+			specification: t.specification,
+			resourceType:  resource.Type,
+			problem:       problem,
+		}
+
+		path := []string{sectionResources, resource.LogicalID, "Properties"}
+
+		if !resource.Properties.IsSet() {
+			validator.missing(resource.Position, definition.Properties, map[string]bool{}, path)
+		} else {
+			validator.properties(resource.Properties, definition.Properties, resource.Type, path)
+		}
+
+		findings = append(findings, validator.findings...)
+	}
+
+	return findings
+}
+
+//properties validates an object against the properties of a resource type or property type
+func (v *propertyValidator) properties(value template.Value, definitions map[string]spec.Property, owner string, path []string) {
+	if value.IsIntrinsic() {
+		return
+	}
+
+	if !value.IsObject() {
+		v.report(problemType, value.Position(), path, "%s must be an object", path[len(path)-1])
+		return
+	}
+
+	set := make(map[string]bool)
+
+	for _, entry := range value.Entries() {
+		set[entry.Key] = true
+		propertyPath := appendPath(path, entry.Key)
+
+		definition, ok := definitions[entry.Key]
+		if !ok {
+			message := fmt.Sprintf("%s isn't a property of %s", entry.Key, owner)
+			if suggestion := suggest(entry.Key, definitions); suggestion != "" {
+				message += fmt.Sprintf(". Did you mean %s?", suggestion)
+			}
+
+			v.report(problemUnknown, entry.Position, propertyPath, message)
+			continue
+		}
+
+		v.value(entry.Value, definition, propertyPath)
+	}
+
+	v.missing(value.Position(), definitions, set, path)
+}
+
+//missing reports the required properties that aren't set, in alphabetical order
+func (v *propertyValidator) missing(position template.Position, definitions map[string]spec.Property, set map[string]bool, path []string) {
+	names := make([]string, 0)
+
+	for name, definition := range definitions {
+		if definition.Required && !set[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		v.report(problemRequired, position, path, "%s is required by %s", name, v.resourceType)
+	}
+}
+
+//value validates a property value against its definition
+func (v *propertyValidator) value(value template.Value, definition spec.Property, path []string) {
+	if value.IsIntrinsic() {
+		return
+	}
+
+	name := path[len(path)-1]
+
+	switch {
+	case definition.PrimitiveType != "":
+		v.primitive(value, definition.PrimitiveType, definition.Value, path)
+	case definition.Type == "List":
+		if !value.IsList() {
+			v.report(problemType, value.Position(), path, "%s must be a list", name)
+			return
+		}
+
+		for i, item := range value.List() {
+			v.item(item, definition, appendPath(path, strconv.Itoa(i)))
+		}
+	case definition.Type == "Map":
+		if !value.IsObject() {
+			v.report(problemType, value.Position(), path, "%s must be a map", name)
+			return
+		}
+
+		for _, entry := range value.Entries() {
+			v.item(entry.Value, definition, appendPath(path, entry.Key))
+		}
+	default:
+		v.structured(value, definition.Type, path)
+	}
+}
+
+//item validates an item of a list or map against the item type of its definition
+func (v *propertyValidator) item(value template.Value, definition spec.Property, path []string) {
+	if value.IsIntrinsic() {
+		return
+	}
+
+	if definition.PrimitiveItemType != "" {
+		v.primitive(value, definition.PrimitiveItemType, definition.Value, path)
+		return
+	}
+
+	v.structured(value, definition.ItemType, path)
+}
+
+//structured validates an object against a property type. Property types that aren't in the specification are skipped
+func (v *propertyValidator) structured(value template.Value, name string, path []string) {
+	propertyType, ok := v.specification.PropertyType(v.resourceType, name)
+	if !ok {
+		return
+	}
+
+	v.properties(value, propertyType.Properties, v.resourceType+"."+name, path)
+}
+
+//primitive validates a primitive value's type and its value type constraints. CloudFormation accepts numbers and booleans as strings, so they are
+//as well
+func (v *propertyValidator) primitive(value template.Value, primitiveType string, constraint *spec.Value, path []string) {
+	name := path[len(path)-1]
+
+	if primitiveType == "Json" || value.IsNull() {
+		return
+	}
+
+	literal, ok := value.Literal()
+	if !ok {
+		v.report(problemType, value.Position(), path, "%s must be a %s", name, strings.ToLower(primitiveType))
+		return
+	}
+
+	switch primitiveType {
+	case "Integer", "Long":
+		if _, err := strconv.ParseInt(literal, 10, 64); err != nil {
+			v.report(problemType, value.Position(), path, "%s must be an integer, not %s", name, literal)
+			return
+		}
+	case "Double":
+		if _, err := strconv.ParseFloat(literal, 64); err != nil {
+			v.report(problemType, value.Position(), path, "%s must be a number, not %s", name, literal)
+			return
+		}
+	case "Boolean":
+		if lower := strings.ToLower(literal); lower != "true" && lower != "false" {
+			v.report(problemType, value.Position(), path, "%s must be true or false, not %s", name, literal)
+			return
+		}
+	}
+
+	if constraint != nil {
+		v.constrain(literal, value.Position(), v.specification.ValueTypes[constraint.ValueType], path)
+	}
+}
+
+//constrain validates a primitive value against the allowed values, pattern, length and range of its value type. Patterns Go can't compile are
+//skipped
+func (v *propertyValidator) constrain(value string, position template.Position, valueType spec.ValueType, path []string) {
+	name := path[len(path)-1]
+
+	if len(valueType.AllowedValues) > 0 && !containsString(valueType.AllowedValues, value) {
+		v.report(problemAllowedValue, position, path, "%s must be one of %s, not %s", name, strings.Join(valueType.AllowedValues, ", "), value)
+	}
+
+	if valueType.AllowedPatternRegex != "" {
+		pattern, err := regexp.Compile(valueType.AllowedPatternRegex)
+		if err == nil && !pattern.MatchString(value) {
+			v.report(problemPattern, position, path, "%s %s doesn't match the pattern %s", name, value, valueType.AllowedPatternRegex)
+		}
+	}
+
+	if valueType.StringMin != nil && float64(len(value)) < *valueType.StringMin {
+		v.report(problemPattern, position, path, "%s must be at least %v characters", name, *valueType.StringMin)
+	}
+
+	if valueType.StringMax != nil && float64(len(value)) > *valueType.StringMax {
+		v.report(problemPattern, position, path, "%s must be at most %v characters", name, *valueType.StringMax)
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	if valueType.NumberMin != nil && number < *valueType.NumberMin {
+		v.report(problemPattern, position, path, "%s must be at least %v", name, *valueType.NumberMin)
+	}
+
+	if valueType.NumberMax != nil && number > *valueType.NumberMax {
+		v.report(problemPattern, position, path, "%s must be at most %v", name, *valueType.NumberMax)
+	}
+}
+
+func containsString(values []string, expected string) bool {
+	for _, value := range values {
+		if value == expected {
+			return true
+		}
+	}
+
+	return false
+}
+
+//suggest returns the known property closest to an unknown one, if it's close enough to be a typo
+func suggest(name string, definitions map[string]spec.Property) string {
+	suggestion := ""
+	best := maxSuggestionDistance + 1
+
+	for candidate := range definitions {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < best || distance == best && candidate < suggestion {
+			suggestion = candidate
+			best = distance
+		}
+	}
+
+	if best > maxSuggestionDistance {
+		return ""
+	}
+
+	return suggestion
+}
+
+//editDistance is the Levenshtein distance between two strings
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	smallest := values[0]
+
+	for _, value := range values[1:] {
+		if value < smallest {
+			smallest = value
+		}
+	}
+
+	return smallest
+}
+
+//checkUnknownProperties finds properties the resource type doesn't have. The bundled specification is a subset that can fall behind the
+//published one, so properties it doesn't know are only warnings
+func checkUnknownProperties(t *Template) []Finding {
+	findings := checkProperties(t, problemUnknown)
+
+	if t.specification != nil && t.specification.Source == spec.SourceBundled {
+		for i := range findings {
+			findings[i].Severity = SeverityWarning
+			findings[i].Message += ". The bundled resource specification may be missing it, update it with cirrus spec update"
+		}
+	}
+
+	return findings
+}
+
+func checkRequiredProperties(t *Template) []Finding {
+	return checkProperties(t, problemRequired)
+}
+
+func checkPropertyTypes(t *Template) []Finding {
+	return checkProperties(t, problemType)
+}
+
+func checkAllowedValues(t *Template) []Finding {
+	return checkProperties(t, problemAllowedValue)
+}
+
+func checkPatterns(t *Template) []Finding {
+	return checkProperties(t, problemPattern)
+}
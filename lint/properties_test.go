@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/blueseph/cirrus/spec"
+)
+
+func TestUnknownProperties(t *testing.T) {
+	bundled, err := spec.Bundled()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := spec.Parse([]byte(`{"ResourceTypes": {"AWS::SNS::Topic": {"Properties": {"TopicName": {"PrimitiveType": "String"}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated.Source = "specification.json"
+
+	tests := []struct {
+		name          string
+		template      string
+		specification *spec.Specification
+		want          []Severity
+	}{
+		{
+			name:          "recent properties in the bundled specification",
+			template:      "Resources:\n  Function:\n    Type: AWS::Lambda::Function\n    Properties:\n      Architectures: [arm64]\n      EphemeralStorage:\n        Size: 1024\n  Table:\n    Type: AWS::DynamoDB::Table\n    Properties:\n      TableClass: STANDARD\n  Key:\n    Type: AWS::KMS::Key\n    Properties:\n      MultiRegion: true\n",
+			specification: bundled,
+			want:          []Severity{},
+		},
+		{
+			name:          "unknown property in the bundled specification",
+			template:      "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicNam: topic\n",
+			specification: bundled,
+			want:          []Severity{SeverityWarning},
+		},
+		{
+			name:          "unknown property in an updated specification",
+			template:      "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicNam: topic\n",
+			specification: updated,
+			want:          []Severity{SeverityError},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := make([]Severity, 0)
+
+			for _, finding := range Run([]byte(test.template), test.specification) {
+				if finding.RuleID == "CE3001" {
+					got = append(got, finding.Severity)
+				}
+			}
+
+			if len(got) != len(test.want) {
+				t.Fatalf("got CE3001 findings %v, want %v", got, test.want)
+			}
+
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("got CE3001 findings %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
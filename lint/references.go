@@ -0,0 +1,150 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blueseph/cirrus/template"
+)
+
+//referenceKind is how a template refers to a parameter, resource, mapping or condition
+type referenceKind string
+
+const (
+	referenceRef       referenceKind = "Ref"
+	referenceGetAtt    referenceKind = "Fn::GetAtt"
+	referenceFindInMap referenceKind = "Fn::FindInMap"
+	referenceCondition referenceKind = "Condition"
+)
+
+//referencingSections are the template sections that can refer to other entries
+var referencingSections []string = []string{
+	sectionConditions,
+	sectionResources,
+	sectionOutputs,
+	"Rules",
+	"Globals",
+}
+
+//subVariable matches the variables of a Fn::Sub string. Variables starting with ! are literals
+var subVariable = regexp.MustCompile(`\$\{([^!}][^}]*)\}`)
+
+//reference is a use of a template entry
+type reference struct {
+	Kind     referenceKind
+	Target   string
+	Position template.Position
+	Path     []string
+}
+
+//appendPath returns a copy of the path with the segment added, so sibling paths don't share a backing array
+func appendPath(path []string, segment string) []string {
+	appended := make([]string, len(path), len(path)+1)
+	copy(appended, path)
+
+	return append(appended, segment)
+}
+
+//references returns every reference made by the template
+func (t *Template) references() []reference {
+	found := make([]reference, 0)
+
+	for _, section := range referencingSections {
+		for _, entry := range t.Section(section) {
+			collectReferences(entry.Value, []string{section, entry.Key}, &found)
+		}
+	}
+
+	return found
+}
+
+func collectReferences(value template.Value, path []string, found *[]reference) {
+	if intrinsic, ok := value.Intrinsic(); ok {
+		collectIntrinsic(intrinsic.Name, intrinsic.Argument, path, found)
+		collectReferences(intrinsic.Argument, appendPath(path, intrinsic.Name), found)
+
+		return
+	}
+
+	for _, entry := range value.Entries() {
+		if entry.Key == "Condition" {
+			collectIntrinsic(entry.Key, entry.Value, path, found)
+		}
+
+		collectReferences(entry.Value, appendPath(path, entry.Key), found)
+	}
+
+	for i, item := range value.List() {
+		collectReferences(item, appendPath(path, fmt.Sprint(i)), found)
+	}
+}
+
+//collectIntrinsic records the references made by an intrinsic function, or by the Condition attribute of a resource or output. Functions that
+//aren't references are ignored
+func collectIntrinsic(name string, argument template.Value, path []string, found *[]reference) {
+	add := func(kind referenceKind, target string) {
+		*found = append(*found, reference{Kind: kind, Target: target, Position: argument.Position(), Path: appendPath(path, name)})
+	}
+
+	literal, isLiteral := argument.Literal()
+
+	first, firstIsLiteral := literal, isLiteral
+	if list := argument.List(); len(list) > 0 {
+		first, firstIsLiteral = list[0].Literal()
+	}
+
+	switch name {
+	case "Ref":
+		if isLiteral {
+			add(referenceRef, literal)
+		}
+	case "Condition":
+		if isLiteral {
+			add(referenceCondition, literal)
+		}
+	case "Fn::GetAtt":
+		if isLiteral {
+			add(referenceGetAtt, strings.SplitN(literal, ".", 2)[0])
+		} else if argument.IsList() && firstIsLiteral {
+			add(referenceGetAtt, first)
+		}
+	case "Fn::FindInMap":
+		if argument.IsList() && firstIsLiteral {
+			add(referenceFindInMap, first)
+		}
+	case "Fn::If":
+		if argument.IsList() && firstIsLiteral {
+			add(referenceCondition, first)
+		}
+	case "Fn::Sub":
+		if !firstIsLiteral {
+			return
+		}
+
+		variables := make(map[string]bool)
+		if list := argument.List(); len(list) > 1 {
+			for _, entry := range list[1].Entries() {
+				variables[entry.Key] = true
+			}
+		}
+
+		for _, match := range subVariable.FindAllStringSubmatch(first, -1) {
+			variable := strings.TrimSpace(match[1])
+			if variables[variable] {
+				continue
+			}
+
+			if strings.Contains(variable, ".") {
+				add(referenceGetAtt, strings.SplitN(variable, ".", 2)[0])
+			} else {
+				add(referenceRef, variable)
+			}
+		}
+	}
+}
+
+//isPseudoParameter determines if a Ref target is a pseudo parameter such as AWS::Region
+func isPseudoParameter(target string) bool {
+	return strings.HasPrefix(target, "AWS::")
+}
@@ -0,0 +1,347 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/spec"
+	"github.com/blueseph/cirrus/template"
+)
+
+//Sources of findings
+const (
+	//Source is the source of findings from the built-in rules
+	Source string = "cirrus"
+
+	//SourceCfnLint is the source of findings from cfn-lint
+	SourceCfnLint string = "cfn-lint"
+)
+
+//Output limits of a CloudFormation template
+const (
+	maxOutputs           int = 200
+	maxOutputNameLength  int = 255
+	maxExportNameLength  int = 255
+	maxDescriptionLength int = 1024
+)
+
+//ruleIDParse is the rule ID of a template that can't be parsed. It isn't a rule since no other rule can run without a parsed template
+const ruleIDParse string = "CE0000"
+
+//hardCodedAccountID matches account IDs alone or in ARNs, whose partition and region may be Fn::Sub variables such as ${AWS::Region}
+var (
+	hardCodedAccountID = regexp.MustCompile(`^\d{12}$|arn:(?:[\w-]|\$\{[^}]*\})+:(?:[\w-]|\$\{[^}]*\})*:(?:[\w-]|\$\{[^}]*\})*:(\d{12}):`)
+	hardCodedRegion    = regexp.MustCompile(`\b(?:us-gov|us|eu|ap|sa|ca|me|af|il|cn)-(?:north|south|east|west|central|northeast|southeast|northwest|southwest)-\d\b`)
+)
+
+//Rule checks a template for one kind of problem. Findings have the rule's severity, unless the check gives them another
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+
+	check func(t *Template) []Finding
+}
+
+//Rules are the built-in rules, run in order
+var Rules []Rule = []Rule{
+	{ID: "CE0001", Severity: SeverityError, Description: "Logical IDs are unique", check: checkDuplicateLogicalIDs},
+	{ID: "CE1001", Severity: SeverityError, Description: "Ref targets a parameter or resource", check: checkUndefinedRefs},
+	{ID: "CE1002", Severity: SeverityError, Description: "Fn::GetAtt targets a resource", check: checkUndefinedGetAtts},
+	{ID: "CE1003", Severity: SeverityError, Description: "Conditions are defined", check: checkUndefinedConditions},
+	{ID: "CE1004", Severity: SeverityError, Description: "Fn::FindInMap targets a mapping", check: checkUndefinedMappings},
+	{ID: "CE1005", Severity: SeverityError, Description: "DependsOn targets other resources", check: checkDependsOn},
+	{ID: "CE3001", Severity: SeverityError, Description: "Properties are defined by the resource type", check: checkUnknownProperties},
+	{ID: "CE3002", Severity: SeverityError, Description: "Required properties are set", check: checkRequiredProperties},
+	{ID: "CE3003", Severity: SeverityError, Description: "Properties have the right type", check: checkPropertyTypes},
+	{ID: "CE3004", Severity: SeverityError, Description: "Properties have an allowed value", check: checkAllowedValues},
+	{ID: "CE3005", Severity: SeverityError, Description: "Properties match their pattern, length and range", check: checkPatterns},
+	{ID: "CE4001", Severity: SeverityError, Description: "Outputs are within CloudFormation limits", check: checkOutputLimits},
+	{ID: "CW2001", Severity: SeverityWarning, Description: "Parameters are used", check: checkUnusedParameters},
+	{ID: "CW2002", Severity: SeverityWarning, Description: "Mappings are used", check: checkUnusedMappings},
+	{ID: "CW2003", Severity: SeverityWarning, Description: "Conditions are used", check: checkUnusedConditions},
+	{ID: "CW3001", Severity: SeverityWarning, Description: "Stateful resources set a DeletionPolicy", check: checkStatefulDeletionPolicy},
+	{ID: "CW3002", Severity: SeverityWarning, Description: "Account IDs aren't hard-coded", check: checkHardCodedAccountIDs},
+	{ID: "CW3003", Severity: SeverityWarning, Description: "Regions aren't hard-coded", check: checkHardCodedRegions},
+}
+
+//Run parses a JSON or YAML template and checks it against the built-in rules. Resource properties are validated against the specification, unless
+//it's nil. A template that can't be parsed is a single error finding
+func Run(contents []byte, specification *spec.Specification) []Finding {
+	parsed, err := template.Parse(contents)
+	if err != nil {
+		return []Finding{{
+			RuleID:   ruleIDParse,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("Template can't be parsed: %s", err),
+			Source:   Source,
+		}}
+	}
+
+	linted := &Template{Template: parsed, specification: specification}
+
+	findings := make([]Finding, 0)
+
+	for _, rule := range Rules {
+		for _, finding := range rule.check(linted) {
+			finding.RuleID = rule.ID
+			finding.Source = Source
+
+			if finding.Severity == "" {
+				finding.Severity = rule.Severity
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+func newFinding(position template.Position, path []string, format string, args ...interface{}) Finding {
+	return Finding{
+		Message: fmt.Sprintf(format, args...),
+		Path:    path,
+		Line:    position.Line,
+		Column:  position.Column,
+	}
+}
+
+func checkDuplicateLogicalIDs(t *Template) []Finding {
+	findings := make([]Finding, 0)
+
+	for _, section := range []string{sectionParameters, sectionMappings, sectionConditions, sectionResources, sectionOutputs} {
+		seen := make(map[string]bool)
+
+		for _, entry := range t.Section(section) {
+			if seen[entry.Key] {
+				findings = append(findings, newFinding(entry.Position, []string{section, entry.Key}, "%s is defined more than once in %s", entry.Key, section))
+			}
+
+			seen[entry.Key] = true
+		}
+	}
+
+	parameters := t.names(sectionParameters)
+	for _, resource := range t.Resources {
+		if parameters[resource.LogicalID] {
+			findings = append(findings, newFinding(resource.Position, []string{sectionResources, resource.LogicalID}, "%s is both a parameter and a resource", resource.LogicalID))
+		}
+	}
+
+	return findings
+}
+
+//checkUndefinedReferences finds references of a kind whose target isn't defined. Transforms such as AWS::Serverless add resources that aren't in the
+//template, so resource references aren't checked for transformed templates
+func checkUndefinedReferences(t *Template, kind referenceKind, defined map[string]bool, resources bool, describe string) []Finding {
+	findings := make([]Finding, 0)
+
+	if resources && t.IsTransformed() {
+		return findings
+	}
+
+	for _, reference := range t.references() {
+		if reference.Kind != kind || defined[reference.Target] {
+			continue
+		}
+
+		if kind == referenceRef && isPseudoParameter(reference.Target) {
+			continue
+		}
+
+		findings = append(findings, newFinding(reference.Position, reference.Path, "%s %s isn't defined in the template", describe, reference.Target))
+	}
+
+	return findings
+}
+
+func checkUndefinedRefs(t *Template) []Finding {
+	defined := t.names(sectionResources)
+	for name := range t.names(sectionParameters) {
+		defined[name] = true
+	}
+
+	return checkUndefinedReferences(t, referenceRef, defined, true, "Parameter or resource")
+}
+
+func checkUndefinedGetAtts(t *Template) []Finding {
+	return checkUndefinedReferences(t, referenceGetAtt, t.names(sectionResources), true, "Resource")
+}
+
+func checkUndefinedConditions(t *Template) []Finding {
+	return checkUndefinedReferences(t, referenceCondition, t.names(sectionConditions), false, "Condition")
+}
+
+func checkUndefinedMappings(t *Template) []Finding {
+	return checkUndefinedReferences(t, referenceFindInMap, t.names(sectionMappings), false, "Mapping")
+}
+
+func checkDependsOn(t *Template) []Finding {
+	findings := make([]Finding, 0)
+	resources := t.names(sectionResources)
+
+	for _, resource := range t.Resources {
+		path := []string{sectionResources, resource.LogicalID, "DependsOn"}
+
+		for _, target := range resource.Definition.Field("DependsOn").Items() {
+			logicalID, ok := target.Literal()
+
+			switch {
+			case !ok:
+				findings = append(findings, newFinding(target.Position(), path, "DependsOn of %s must be a logical ID or a list of logical IDs", resource.LogicalID))
+			case logicalID == resource.LogicalID:
+				findings = append(findings, newFinding(target.Position(), path, "%s depends on itself", resource.LogicalID))
+			case !resources[logicalID] && !t.IsTransformed():
+				findings = append(findings, newFinding(target.Position(), path, "%s depends on %s, which isn't a resource in the template", resource.LogicalID, logicalID))
+			}
+		}
+	}
+
+	return findings
+}
+
+func checkOutputLimits(t *Template) []Finding {
+	findings := make([]Finding, 0)
+
+	if len(t.Outputs) > maxOutputs {
+		findings = append(findings, newFinding(t.Outputs[maxOutputs].Position, []string{sectionOutputs}, "Template has %d outputs, more than the limit of %d", len(t.Outputs), maxOutputs))
+	}
+
+	for _, output := range t.Outputs {
+		path := []string{sectionOutputs, output.Name}
+
+		if len(output.Name) > maxOutputNameLength {
+			findings = append(findings, newFinding(output.Position, path, "Output name is %d characters, more than the limit of %d", len(output.Name), maxOutputNameLength))
+		}
+
+		description := output.Definition.Field("Description")
+		if literal, ok := description.Literal(); ok && len(literal) > maxDescriptionLength {
+			findings = append(findings, newFinding(description.Position(), appendPath(path, "Description"), "Description of %s is %d bytes, more than the limit of %d", output.Name, len(literal), maxDescriptionLength))
+		}
+
+		if literal, ok := output.ExportName.Literal(); ok && len(literal) > maxExportNameLength {
+			findings = append(findings, newFinding(output.ExportName.Position(), appendPath(path, "Export"), "Export name of %s is %d characters, more than the limit of %d", output.Name, len(literal), maxExportNameLength))
+		}
+	}
+
+	return findings
+}
+
+//checkUnused finds the entries of a section that nothing refers to with the given kinds of reference
+func checkUnused(t *Template, section string, describe string, kinds ...referenceKind) []Finding {
+	findings := make([]Finding, 0)
+	used := make(map[string]bool)
+
+	for _, reference := range t.references() {
+		for _, kind := range kinds {
+			if reference.Kind == kind {
+				used[reference.Target] = true
+			}
+		}
+	}
+
+	for _, entry := range t.Section(section) {
+		if !used[entry.Key] {
+			findings = append(findings, newFinding(entry.Position, []string{section, entry.Key}, "%s %s isn't used", describe, entry.Key))
+		}
+	}
+
+	return findings
+}
+
+func checkUnusedParameters(t *Template) []Finding {
+	return checkUnused(t, sectionParameters, "Parameter", referenceRef)
+}
+
+func checkUnusedMappings(t *Template) []Finding {
+	return checkUnused(t, sectionMappings, "Mapping", referenceFindInMap)
+}
+
+func checkUnusedConditions(t *Template) []Finding {
+	return checkUnused(t, sectionConditions, "Condition", referenceCondition)
+}
+
+func checkStatefulDeletionPolicy(t *Template) []Finding {
+	findings := make([]Finding, 0)
+
+	for _, resource := range t.Resources {
+		if !isStatefulType(resource.Type) || resource.DeletionPolicy.IsSet() {
+			continue
+		}
+
+		findings = append(findings, newFinding(resource.Position, []string{sectionResources, resource.LogicalID}, "%s is a stateful %s without a DeletionPolicy, so its data is deleted along with it. Set DeletionPolicy to Retain or Snapshot to keep it", resource.LogicalID, resource.Type))
+	}
+
+	return findings
+}
+
+func isStatefulType(resourceType string) bool {
+	for _, statefulType := range data.StatefulResourceTypes {
+		if statefulType == resourceType {
+			return true
+		}
+	}
+
+	return false
+}
+
+//walkStrings calls the visitor for every literal under the value, including Fn::Sub strings. The targets of Ref and Fn::GetAtt are logical IDs, so
+//they're skipped
+func walkStrings(value template.Value, path []string, visit func(literal string, position template.Position, path []string)) {
+	if intrinsic, ok := value.Intrinsic(); ok {
+		if intrinsic.Name != "Ref" && intrinsic.Name != "Fn::GetAtt" {
+			walkStrings(intrinsic.Argument, appendPath(path, intrinsic.Name), visit)
+		}
+
+		return
+	}
+
+	if literal, ok := value.Literal(); ok {
+		visit(literal, value.Position(), path)
+		return
+	}
+
+	for _, entry := range value.Entries() {
+		walkStrings(entry.Value, appendPath(path, entry.Key), visit)
+	}
+
+	for i, item := range value.List() {
+		walkStrings(item, appendPath(path, fmt.Sprint(i)), visit)
+	}
+}
+
+//checkHardCoded finds strings in resources and outputs matching the pattern. Parameters and mappings are where values like these belong, so they
+//aren't checked
+func checkHardCoded(t *Template, pattern *regexp.Regexp, message string) []Finding {
+	findings := make([]Finding, 0)
+
+	for _, section := range []string{sectionResources, sectionOutputs} {
+		for _, entry := range t.Section(section) {
+			walkStrings(entry.Value, []string{section, entry.Key}, func(literal string, position template.Position, path []string) {
+				match := pattern.FindStringSubmatch(literal)
+				if match == nil {
+					return
+				}
+
+				value := match[0]
+				if len(match) > 1 && match[1] != "" {
+					value = match[1]
+				}
+
+				findings = append(findings, newFinding(position, path, message, value))
+			})
+		}
+	}
+
+	return findings
+}
+
+func checkHardCodedAccountIDs(t *Template) []Finding {
+	return checkHardCoded(t, hardCodedAccountID, "Hard-coded account ID %s. Use the AWS::AccountId pseudo parameter so the template deploys to any account")
+}
+
+func checkHardCodedRegions(t *Template) []Finding {
+	return checkHardCoded(t, hardCodedRegion, "Hard-coded region %s. Use the AWS::Region pseudo parameter so the template deploys to any region")
+}
@@ -0,0 +1,278 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//countFindings returns how many findings a rule has
+func countFindings(findings []Finding, ruleID string) int {
+	count := 0
+
+	for _, finding := range findings {
+		if finding.RuleID == ruleID {
+			count++
+		}
+	}
+
+	return count
+}
+
+//outputs returns an Outputs section with the given number of outputs
+func outputs(count int) string {
+	section := "Outputs:\n"
+	for i := 0; i < count; i++ {
+		section += fmt.Sprintf("  Output%d:\n    Value: value\n", i)
+	}
+
+	return section
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		ruleID   string
+		want     int
+	}{
+		{
+			name:     "unparseable template",
+			template: "Resources: [",
+			ruleID:   "CE0000",
+			want:     1,
+		},
+		{
+			name:     "duplicate resource",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n  Topic:\n    Type: AWS::SNS::Topic\n",
+			ruleID:   "CE0001",
+			want:     1,
+		},
+		{
+			name:     "parameter and resource with the same name",
+			template: "Parameters:\n  Topic:\n    Type: String\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Ref Topic\n",
+			ruleID:   "CE0001",
+			want:     1,
+		},
+		{
+			name:     "Ref to an undefined target",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Ref Missing\n",
+			ruleID:   "CE1001",
+			want:     1,
+		},
+		{
+			name:     "full form Ref to an undefined target",
+			template: `{"Resources": {"Topic": {"Type": "AWS::SNS::Topic", "Properties": {"TopicName": {"Ref": "Missing"}}}}}`,
+			ruleID:   "CE1001",
+			want:     1,
+		},
+		{
+			name:     "Ref to a pseudo parameter",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Ref AWS::StackName\n",
+			ruleID:   "CE1001",
+			want:     0,
+		},
+		{
+			name:     "Ref to an undefined target of a transformed template",
+			template: "Transform: AWS::Serverless-2016-10-31\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Ref Generated\n",
+			ruleID:   "CE1001",
+			want:     0,
+		},
+		{
+			name:     "Fn::Sub variable that's undefined",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Sub '${Missing}-topic'\n",
+			ruleID:   "CE1001",
+			want:     1,
+		},
+		{
+			name:     "Fn::Sub variables that are given, literal or pseudo parameters",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Sub ['${Name}-${!Literal}-${AWS::Region}', {Name: topic}]\n",
+			ruleID:   "CE1001",
+			want:     0,
+		},
+		{
+			name:     "short form Fn::GetAtt of an undefined resource",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !GetAtt Missing.Name\n",
+			ruleID:   "CE1002",
+			want:     1,
+		},
+		{
+			name:     "full form Fn::GetAtt of an undefined resource",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName:\n        Fn::GetAtt: [Missing, Name]\n",
+			ruleID:   "CE1002",
+			want:     1,
+		},
+		{
+			name:     "Fn::Sub attribute of an undefined resource",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Sub '${Missing.Name}'\n",
+			ruleID:   "CE1002",
+			want:     1,
+		},
+		{
+			name:     "Fn::GetAtt of a defined resource",
+			template: "Resources:\n  Queue:\n    Type: AWS::SQS::Queue\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !GetAtt Queue.QueueName\n",
+			ruleID:   "CE1002",
+			want:     0,
+		},
+		{
+			name:     "undefined resource condition",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Condition: Missing\n",
+			ruleID:   "CE1003",
+			want:     1,
+		},
+		{
+			name:     "Fn::If of an undefined condition",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !If [Missing, a, b]\n",
+			ruleID:   "CE1003",
+			want:     1,
+		},
+		{
+			name:     "Fn::FindInMap of an undefined mapping",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !FindInMap [Missing, a, b]\n",
+			ruleID:   "CE1004",
+			want:     1,
+		},
+		{
+			name:     "DependsOn itself",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    DependsOn: Topic\n",
+			ruleID:   "CE1005",
+			want:     1,
+		},
+		{
+			name:     "DependsOn an undefined resource",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    DependsOn: [Queue, Missing]\n  Queue:\n    Type: AWS::SQS::Queue\n",
+			ruleID:   "CE1005",
+			want:     1,
+		},
+		{
+			name:     "DependsOn an intrinsic function",
+			template: "Parameters:\n  Name:\n    Type: String\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n    DependsOn: !Ref Name\n",
+			ruleID:   "CE1005",
+			want:     1,
+		},
+		{
+			name:     "DependsOn other resources",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    DependsOn: [Queue]\n  Queue:\n    Type: AWS::SQS::Queue\n",
+			ruleID:   "CE1005",
+			want:     0,
+		},
+		{
+			name:     "too many outputs",
+			template: outputs(maxOutputs + 1),
+			ruleID:   "CE4001",
+			want:     1,
+		},
+		{
+			name:     "as many outputs as the limit",
+			template: outputs(maxOutputs),
+			ruleID:   "CE4001",
+			want:     0,
+		},
+		{
+			name:     "output name over the limit",
+			template: "Outputs:\n  " + strings.Repeat("a", maxOutputNameLength+1) + ":\n    Value: value\n",
+			ruleID:   "CE4001",
+			want:     1,
+		},
+		{
+			name:     "output description over the limit",
+			template: "Outputs:\n  Name:\n    Value: value\n    Description: " + strings.Repeat("a", maxDescriptionLength+1) + "\n",
+			ruleID:   "CE4001",
+			want:     1,
+		},
+		{
+			name:     "export name over the limit",
+			template: "Outputs:\n  Name:\n    Value: value\n    Export:\n      Name: " + strings.Repeat("a", maxExportNameLength+1) + "\n",
+			ruleID:   "CE4001",
+			want:     1,
+		},
+		{
+			name:     "unused parameter",
+			template: "Parameters:\n  Name:\n    Type: String\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n",
+			ruleID:   "CW2001",
+			want:     1,
+		},
+		{
+			name:     "parameter used in Fn::Sub",
+			template: "Parameters:\n  Name:\n    Type: String\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Sub '${Name}-topic'\n",
+			ruleID:   "CW2001",
+			want:     0,
+		},
+		{
+			name:     "unused mapping",
+			template: "Mappings:\n  Names:\n    a:\n      b: c\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n",
+			ruleID:   "CW2002",
+			want:     1,
+		},
+		{
+			name:     "mapping used in Fn::FindInMap",
+			template: "Mappings:\n  Names:\n    a:\n      b: c\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName:\n        Fn::FindInMap: [Names, a, b]\n",
+			ruleID:   "CW2002",
+			want:     0,
+		},
+		{
+			name:     "unused condition",
+			template: "Conditions:\n  IsProd: !Equals [a, b]\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n",
+			ruleID:   "CW2003",
+			want:     1,
+		},
+		{
+			name:     "condition used by another condition",
+			template: "Conditions:\n  IsProd: !Equals [a, b]\n  IsNotProd: !Not [!Condition IsProd]\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Condition: IsNotProd\n",
+			ruleID:   "CW2003",
+			want:     0,
+		},
+		{
+			name:     "stateful resource without a DeletionPolicy",
+			template: "Resources:\n  Table:\n    Type: AWS::DynamoDB::Table\n",
+			ruleID:   "CW3001",
+			want:     1,
+		},
+		{
+			name:     "stateful resource with a DeletionPolicy",
+			template: "Resources:\n  Table:\n    Type: AWS::DynamoDB::Table\n    DeletionPolicy: Retain\n",
+			ruleID:   "CW3001",
+			want:     0,
+		},
+		{
+			name:     "hard-coded account ID in an ARN",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      KmsMasterKeyId: arn:aws:kms:us-east-1:123456789012:key/abc\n",
+			ruleID:   "CW3002",
+			want:     1,
+		},
+		{
+			name:     "hard-coded account ID in Fn::Sub",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      KmsMasterKeyId: !Sub 'arn:aws:kms:${AWS::Region}:123456789012:key/abc'\n",
+			ruleID:   "CW3002",
+			want:     1,
+		},
+		{
+			name:     "account ID in a parameter default",
+			template: "Parameters:\n  Account:\n    Type: String\n    Default: '123456789012'\nResources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Ref Account\n",
+			ruleID:   "CW3002",
+			want:     0,
+		},
+		{
+			name:     "hard-coded region",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: topic-us-east-1\n",
+			ruleID:   "CW3003",
+			want:     1,
+		},
+		{
+			name:     "region from the pseudo parameter",
+			template: "Resources:\n  Topic:\n    Type: AWS::SNS::Topic\n    Properties:\n      TopicName: !Sub 'topic-${AWS::Region}'\n",
+			ruleID:   "CW3003",
+			want:     0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings := Run([]byte(test.template), nil)
+
+			if got := countFindings(findings, test.ruleID); got != test.want {
+				t.Errorf("got %d %s findings, want %d: %+v", got, test.ruleID, test.want, findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"github.com/blueseph/cirrus/spec"
+	"github.com/blueseph/cirrus/template"
+)
+
+//Template sections that hold logical IDs
+const (
+	sectionParameters string = "Parameters"
+	sectionMappings   string = "Mappings"
+	sectionConditions string = "Conditions"
+	sectionResources  string = "Resources"
+	sectionOutputs    string = "Outputs"
+)
+
+//Template is a template being linted, along with the specification its resource properties are validated against
+type Template struct {
+	*template.Template
+
+	specification *spec.Specification
+}
+
+//names returns the set of names in a template section
+func (t *Template) names(section string) map[string]bool {
+	found := make(map[string]bool)
+
+	for _, entry := range t.Section(section) {
+		found[entry.Key] = true
+	}
+
+	return found
+}
@@ -15,6 +15,11 @@ func main() {
 		Commands: []*cli.Command{
 			cmd.UpCommand,
 			cmd.DownCommand,
+			cmd.ProtectCommand,
+			cmd.UnprotectCommand,
+			cmd.StatusCommand,
+			cmd.LintCommand,
+			cmd.SpecCommand,
 		},
 	}
 
@@ -0,0 +1,327 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/template"
+	"gopkg.in/yaml.v3"
+)
+
+//Assertion checks the values at a path of a resource, such as Properties.BucketEncryption. Paths use [*] for every item of a list and [n] for a
+//single item. Values set with intrinsic functions other than a Ref to a parameter can't be resolved before deploying, so they're skipped by
+//comparisons
+type Assertion struct {
+	Path      string   `yaml:"path"`
+	Exists    *bool    `yaml:"exists"`
+	Equals    *string  `yaml:"equals"`
+	NotEquals *string  `yaml:"notEquals"`
+	In        []string `yaml:"in"`
+	NotIn     []string `yaml:"notIn"`
+	Contains  []string `yaml:"contains"`
+	Matches   string   `yaml:"matches"`
+}
+
+//Rule is an organisation rule for resources of the given types, or every resource when no types are given. Types can use wildcards such as
+//AWS::S3::*. A rule only applies to resources that pass all of its when assertions
+type Rule struct {
+	Name          string      `yaml:"name"`
+	Message       string      `yaml:"message"`
+	ResourceTypes []string    `yaml:"resourceTypes"`
+	When          []Assertion `yaml:"when"`
+	Assert        []Assertion `yaml:"assert"`
+	RequiredTags  []string    `yaml:"requiredTags"`
+}
+
+//Policy is a rules file
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+//Violation is a resource that breaks a rule
+type Violation struct {
+	Rule         string
+	Message      string
+	LogicalID    string
+	ResourceType string
+	Line         int
+}
+
+//GetPolicies loads the rules files at the given locations. Missing files are skipped unless required, so a default location can be optional
+func GetPolicies(locations []string, required bool) ([]Rule, error) {
+	rules := make([]Rule, 0)
+
+	for _, location := range locations {
+		contents, err := ioutil.ReadFile(location)
+		if err != nil {
+			if required {
+				return nil, errors.New(colors.Error(fmt.Sprintf("Could not find policy %s", location)))
+			}
+
+			continue
+		}
+
+		var policy Policy
+		if err := yaml.Unmarshal(contents, &policy); err != nil {
+			return nil, errors.New(colors.Error(fmt.Sprintf("Unable to load policy %s. %s", location, err)))
+		}
+
+		for _, rule := range policy.Rules {
+			if err := validateRule(rule); err != nil {
+				return nil, errors.New(colors.Error(fmt.Sprintf("Unable to load policy %s. %s", location, err)))
+			}
+		}
+
+		rules = append(rules, policy.Rules...)
+	}
+
+	return rules, nil
+}
+
+func validateRule(rule Rule) error {
+	if rule.Name == "" {
+		return errors.New("Every rule needs a name")
+	}
+
+	if len(rule.Assert) == 0 && len(rule.RequiredTags) == 0 {
+		return fmt.Errorf("Rule %s needs assertions or required tags", rule.Name)
+	}
+
+	for _, assertion := range append(append([]Assertion{}, rule.When...), rule.Assert...) {
+		if assertion.Path == "" {
+			return fmt.Errorf("Every assertion of rule %s needs a path", rule.Name)
+		}
+
+		if assertion.Matches != "" {
+			if _, err := regexp.Compile(assertion.Matches); err != nil {
+				return fmt.Errorf("Rule %s has an invalid pattern %s", rule.Name, assertion.Matches)
+			}
+		}
+	}
+
+	return nil
+}
+
+//Evaluate parses a JSON or YAML template and returns the resources that break the rules, ordered by logical ID. Refs to parameters are resolved
+//from the parameters, or the parameter's default. Stack tags count towards required tags, since CloudFormation propagates them to resources
+func Evaluate(contents []byte, parameters []cloudformation.Parameter, tags []cloudformation.Tag, rules []Rule) ([]Violation, error) {
+	parsed, err := template.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newResolver(parsed, parameters)
+
+	stackTags := make(map[string]bool)
+	for _, tag := range tags {
+		stackTags[*tag.Key] = true
+	}
+
+	resources := append([]template.Resource{}, parsed.Resources...)
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].LogicalID < resources[j].LogicalID
+	})
+
+	violations := make([]Violation, 0)
+
+	for _, resource := range resources {
+		for _, rule := range rules {
+			if !appliesTo(rule, resource.Type) || !passesAll(resolver, resource.Definition, rule.When) {
+				continue
+			}
+
+			violation := Violation{
+				Rule:         rule.Name,
+				LogicalID:    resource.LogicalID,
+				ResourceType: resource.Type,
+				Line:         resource.Position.Line,
+			}
+
+			for _, assertion := range rule.Assert {
+				if message, ok := check(resolver, resource.Definition, assertion); !ok {
+					violation.Message = messageOrDefault(rule.Message, message)
+					violations = append(violations, violation)
+				}
+			}
+
+			if missing := missingTags(resolver, resource.Definition, rule.RequiredTags, stackTags); len(missing) > 0 {
+				violation.Message = messageOrDefault(rule.Message, fmt.Sprintf("Missing required tags %s", strings.Join(missing, ", ")))
+				violations = append(violations, violation)
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+func messageOrDefault(message string, fallback string) string {
+	if message == "" {
+		return fallback
+	}
+
+	return message
+}
+
+func appliesTo(rule Rule, resourceType string) bool {
+	if len(rule.ResourceTypes) == 0 {
+		return true
+	}
+
+	for _, pattern := range rule.ResourceTypes {
+		if matched, _ := path.Match(pattern, resourceType); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func passesAll(resolver resolver, resource template.Value, assertions []Assertion) bool {
+	for _, assertion := range assertions {
+		if _, ok := check(resolver, resource, assertion); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+//check evaluates an assertion against a resource. It returns a description of the failure when the assertion doesn't hold
+func check(resolver resolver, resource template.Value, assertion Assertion) (string, bool) {
+	values := resolver.values(resource, assertion.Path)
+	resolved := make([]string, 0, len(values))
+	unresolved := false
+
+	for _, value := range values {
+		if value.Resolved {
+			resolved = append(resolved, value.Value)
+		} else {
+			unresolved = true
+		}
+	}
+
+	if assertion.Exists != nil && *assertion.Exists != (len(values) > 0) {
+		if *assertion.Exists {
+			return fmt.Sprintf("%s must be set", assertion.Path), false
+		}
+
+		return fmt.Sprintf("%s must not be set", assertion.Path), false
+	}
+
+	if assertion.Equals != nil && !allMatch(values, resolved, func(value string) bool { return value == *assertion.Equals }) {
+		return fmt.Sprintf("%s must be %s", assertion.Path, *assertion.Equals), false
+	}
+
+	if assertion.NotEquals != nil && anyMatch(resolved, func(value string) bool { return value == *assertion.NotEquals }) {
+		return fmt.Sprintf("%s must not be %s", assertion.Path, *assertion.NotEquals), false
+	}
+
+	if len(assertion.In) > 0 && !allMatch(values, resolved, func(value string) bool { return contains(assertion.In, value) }) {
+		return fmt.Sprintf("%s must be one of %s", assertion.Path, strings.Join(assertion.In, ", ")), false
+	}
+
+	if len(assertion.NotIn) > 0 && anyMatch(resolved, func(value string) bool { return contains(assertion.NotIn, value) }) {
+		return fmt.Sprintf("%s must not be any of %s", assertion.Path, strings.Join(assertion.NotIn, ", ")), false
+	}
+
+	if len(assertion.Contains) > 0 && !unresolved {
+		for _, expected := range assertion.Contains {
+			if !contains(resolved, expected) {
+				return fmt.Sprintf("%s must include %s", assertion.Path, strings.Join(assertion.Contains, ", ")), false
+			}
+		}
+	}
+
+	if assertion.Matches != "" {
+		pattern := regexp.MustCompile(assertion.Matches)
+		if !allMatch(values, resolved, pattern.MatchString) {
+			return fmt.Sprintf("%s must match %s", assertion.Path, assertion.Matches), false
+		}
+	}
+
+	return "", true
+}
+
+//allMatch determines if every resolved value matches. A path that isn't set doesn't match
+func allMatch(values []value, resolved []string, match func(string) bool) bool {
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, value := range resolved {
+		if !match(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func anyMatch(resolved []string, match func(string) bool) bool {
+	for _, value := range resolved {
+		if match(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, expected string) bool {
+	for _, value := range values {
+		if value == expected {
+			return true
+		}
+	}
+
+	return false
+}
+
+//missingTags returns the required tags that neither the resource nor the stack sets. Tags are read from a list of Key and Value pairs, or a map
+//as used by AWS::Serverless resources. Tags set with intrinsic functions can't be read, so they're assumed to be there
+func missingTags(resolver resolver, resource template.Value, required []string, stackTags map[string]bool) []string {
+	missing := make([]string, 0)
+	if len(required) == 0 {
+		return missing
+	}
+
+	keys := resolver.values(resource, "Properties.Tags[*].Key")
+	for _, key := range resolver.keys(resource, "Properties.Tags") {
+		keys = append(keys, value{Value: key, Resolved: true})
+	}
+
+	resourceTags := make(map[string]bool)
+	for _, key := range keys {
+		if !key.Resolved {
+			return missing
+		}
+
+		resourceTags[key.Value] = true
+	}
+
+	for _, tag := range required {
+		if !resourceTags[tag] && !stackTags[tag] {
+			missing = append(missing, tag)
+		}
+	}
+
+	return missing
+}
+
+//GroupByResource returns the violations of each resource
+func GroupByResource(violations []Violation) map[string][]Violation {
+	groups := make(map[string][]Violation)
+
+	for _, violation := range violations {
+		groups[violation.LogicalID] = append(groups[violation.LogicalID], violation)
+	}
+
+	return groups
+}
@@ -0,0 +1,209 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"gopkg.in/yaml.v3"
+)
+
+//bucket is a template with a bucket and a topic, for rules to be evaluated against
+const bucket string = `Parameters:
+  Env:
+    Type: String
+    Default: prod
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+    Properties:
+      AccessControl: Private
+      BucketName: !Ref Env
+      LoggingConfiguration: !If [IsProd, {DestinationBucketName: logs}, !Ref AWS::NoValue]
+      VersioningConfiguration:
+        Status: Enabled
+      CorsConfiguration:
+        CorsRules:
+          - AllowedMethods: [GET, HEAD]
+          - AllowedMethods: [GET, PUT]
+      Tags:
+        - Key: team
+          Value: core
+  Topic:
+    Type: AWS::SNS::Topic
+    Properties:
+      TopicName: !Sub '${AWS::StackName}-topic'
+      Tags:
+        - Key: !Sub '${AWS::StackName}-owner'
+          Value: core
+`
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		policy     string
+		parameters []cloudformation.Parameter
+		tags       []cloudformation.Tag
+		want       []string
+	}{
+		{
+			name:   "exists when set",
+			policy: "rules:\n- name: versioned\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.VersioningConfiguration.Status\n    exists: true\n",
+			want:   []string{},
+		},
+		{
+			name:   "exists when missing",
+			policy: "rules:\n- name: encrypted\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.BucketEncryption\n    exists: true\n",
+			want:   []string{"Bucket: Properties.BucketEncryption must be set"},
+		},
+		{
+			name:   "doesn't exist when set",
+			policy: "rules:\n- name: no-acl\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.AccessControl\n    exists: false\n",
+			want:   []string{"Bucket: Properties.AccessControl must not be set"},
+		},
+		{
+			name:   "equals",
+			policy: "rules:\n- name: versioned\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.VersioningConfiguration.Status\n    equals: Suspended\n",
+			want:   []string{"Bucket: Properties.VersioningConfiguration.Status must be Suspended"},
+		},
+		{
+			name:   "not equals",
+			policy: "rules:\n- name: private\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.AccessControl\n    notEquals: Private\n",
+			want:   []string{"Bucket: Properties.AccessControl must not be Private"},
+		},
+		{
+			name:   "in",
+			policy: "rules:\n- name: acl\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.AccessControl\n    in: [Private, LogDeliveryWrite]\n",
+			want:   []string{},
+		},
+		{
+			name:   "not in",
+			policy: "rules:\n- name: acl\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.AccessControl\n    notIn: [PublicRead, Private]\n",
+			want:   []string{"Bucket: Properties.AccessControl must not be any of PublicRead, Private"},
+		},
+		{
+			name:   "in checks every item of a list",
+			policy: "rules:\n- name: methods\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.CorsConfiguration.CorsRules[*].AllowedMethods[*]\n    in: [GET, HEAD]\n",
+			want:   []string{"Bucket: Properties.CorsConfiguration.CorsRules[*].AllowedMethods[*] must be one of GET, HEAD"},
+		},
+		{
+			name:   "single list item",
+			policy: "rules:\n- name: methods\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.CorsConfiguration.CorsRules[0].AllowedMethods[*]\n    in: [GET, HEAD]\n",
+			want:   []string{},
+		},
+		{
+			name:   "contains",
+			policy: "rules:\n- name: methods\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.CorsConfiguration.CorsRules[1].AllowedMethods[*]\n    contains: [GET, HEAD]\n",
+			want:   []string{"Bucket: Properties.CorsConfiguration.CorsRules[1].AllowedMethods[*] must include GET, HEAD"},
+		},
+		{
+			name:   "matches",
+			policy: "rules:\n- name: acl\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.AccessControl\n    matches: '^Public'\n",
+			want:   []string{"Bucket: Properties.AccessControl must match ^Public"},
+		},
+		{
+			name:   "when that doesn't hold",
+			policy: "rules:\n- name: versioned\n  when:\n  - path: Properties.AccessControl\n    equals: PublicRead\n  assert:\n  - path: Properties.BucketEncryption\n    exists: true\n",
+			want:   []string{},
+		},
+		{
+			name:   "when that holds",
+			policy: "rules:\n- name: versioned\n  when:\n  - path: Properties.AccessControl\n    equals: Private\n  assert:\n  - path: Properties.BucketEncryption\n    exists: true\n",
+			want:   []string{"Bucket: Properties.BucketEncryption must be set"},
+		},
+		{
+			name:   "resource type wildcard",
+			policy: "rules:\n- name: named\n  resourceTypes: ['AWS::SNS::*']\n  assert:\n  - path: Properties.DisplayName\n    exists: true\n",
+			want:   []string{"Topic: Properties.DisplayName must be set"},
+		},
+		{
+			name:   "every resource without resource types",
+			policy: "rules:\n- name: described\n  assert:\n  - path: Metadata\n    exists: true\n",
+			want:   []string{"Bucket: Metadata must be set", "Topic: Metadata must be set"},
+		},
+		{
+			name:   "Ref to a parameter default",
+			policy: "rules:\n- name: name\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.BucketName\n    equals: prod\n",
+			want:   []string{},
+		},
+		{
+			name:       "Ref to a supplied parameter",
+			policy:     "rules:\n- name: name\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.BucketName\n    equals: prod\n",
+			parameters: []cloudformation.Parameter{{ParameterKey: aws.String("Env"), ParameterValue: aws.String("dev")}},
+			want:       []string{"Bucket: Properties.BucketName must be prod"},
+		},
+		{
+			name:   "unresolved intrinsic function is skipped",
+			policy: "rules:\n- name: name\n  resourceTypes: [AWS::SNS::Topic]\n  assert:\n  - path: Properties.TopicName\n    equals: topic\n  - path: Properties.TopicName\n    matches: '^topic$'\n",
+			want:   []string{},
+		},
+		{
+			name:   "path below an intrinsic function exists",
+			policy: "rules:\n- name: logged\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.LoggingConfiguration.DestinationBucketName\n    exists: true\n",
+			want:   []string{},
+		},
+		{
+			name:   "required tags on the resource",
+			policy: "rules:\n- name: tagged\n  resourceTypes: [AWS::S3::Bucket]\n  requiredTags: [team, owner]\n",
+			want:   []string{"Bucket: Missing required tags owner"},
+		},
+		{
+			name:   "required tags on the stack",
+			policy: "rules:\n- name: tagged\n  resourceTypes: [AWS::S3::Bucket]\n  requiredTags: [team, owner]\n",
+			tags:   []cloudformation.Tag{{Key: aws.String("owner"), Value: aws.String("me")}},
+			want:   []string{},
+		},
+		{
+			name:   "required tags set with an intrinsic function",
+			policy: "rules:\n- name: tagged\n  resourceTypes: [AWS::SNS::Topic]\n  requiredTags: [owner]\n",
+			want:   []string{},
+		},
+		{
+			name:     "required tags as a map",
+			template: "Resources:\n  Function:\n    Type: AWS::Serverless::Function\n    Properties:\n      Tags:\n        team: core\n",
+			policy:   "rules:\n- name: tagged\n  requiredTags: [team, owner]\n",
+			want:     []string{"Function: Missing required tags owner"},
+		},
+		{
+			name:   "rule message replaces the failure",
+			policy: "rules:\n- name: encrypted\n  message: Buckets must be encrypted\n  resourceTypes: [AWS::S3::Bucket]\n  assert:\n  - path: Properties.BucketEncryption\n    exists: true\n",
+			want:   []string{"Bucket: Buckets must be encrypted"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var policy Policy
+			if err := yaml.Unmarshal([]byte(test.policy), &policy); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, rule := range policy.Rules {
+				if err := validateRule(rule); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			template := test.template
+			if template == "" {
+				template = bucket
+			}
+
+			violations, err := Evaluate([]byte(template), test.parameters, test.tags, policy.Rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0)
+			for _, violation := range violations {
+				got = append(got, violation.LogicalID+": "+violation.Message)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package policy
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/template"
+)
+
+//pathSegment matches a segment of an assertion path, such as Ingress[*] or Statement[0]
+var pathSegment = regexp.MustCompile(`^([^\[\]]*)((?:\[(?:\*|\d+)\])*)$`)
+
+//value is a value at a path of a resource. Values set with intrinsic functions that can't be resolved before deploying aren't resolved
+type value struct {
+	Value    string
+	Resolved bool
+}
+
+//resolver reads values from resources, resolving Refs to parameters
+type resolver struct {
+	parameters map[string]string
+}
+
+func newResolver(parsed *template.Template, parameters []cloudformation.Parameter) resolver {
+	resolved := make(map[string]string)
+
+	for _, parameter := range parsed.Parameters {
+		if literal, ok := parameter.Default.Literal(); ok {
+			resolved[parameter.Name] = literal
+		}
+	}
+
+	for _, parameter := range parameters {
+		if parameter.ParameterKey != nil && parameter.ParameterValue != nil {
+			resolved[*parameter.ParameterKey] = *parameter.ParameterValue
+		}
+	}
+
+	return resolver{parameters: resolved}
+}
+
+//resolve returns a template value. Literals are resolved, along with Refs to parameters that have a value
+func (r resolver) resolve(v template.Value) value {
+	if intrinsic, ok := v.Intrinsic(); ok {
+		if parameter, ok := r.parameters[intrinsic.Argument.String()]; ok && intrinsic.Name == "Ref" {
+			return value{Value: parameter, Resolved: true}
+		}
+
+		return value{}
+	}
+
+	if literal, ok := v.Literal(); ok || v.IsNull() {
+		return value{Value: literal, Resolved: true}
+	}
+
+	return value{}
+}
+
+//nodes returns the values at a path of a resource. Intrinsic functions along the way can't be walked into, so they're returned in place of the
+//values below them
+func (r resolver) nodes(resource template.Value, path string) []template.Value {
+	current := []template.Value{resource}
+
+	for _, segment := range strings.Split(path, ".") {
+		match := pathSegment.FindStringSubmatch(segment)
+		if match == nil {
+			return nil
+		}
+
+		next := make([]template.Value, 0)
+
+		for _, node := range current {
+			if node.IsIntrinsic() {
+				next = append(next, node)
+				continue
+			}
+
+			if match[1] != "" {
+				node = node.Field(match[1])
+				if !node.IsSet() {
+					continue
+				}
+			}
+
+			next = append(next, index(node, match[2])...)
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+//index applies the list indexes of a path segment, such as [*] or [0][*], to a value
+func index(node template.Value, indexes string) []template.Value {
+	current := []template.Value{node}
+
+	for _, selector := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(indexes, "["), "]"), "][") {
+		if selector == "" {
+			continue
+		}
+
+		next := make([]template.Value, 0)
+
+		for _, node := range current {
+			items := node.List()
+
+			switch {
+			case node.IsIntrinsic():
+				next = append(next, node)
+			case !node.IsList():
+				continue
+			case selector == "*":
+				next = append(next, items...)
+			default:
+				i, err := strconv.Atoi(selector)
+				if err == nil && i < len(items) {
+					next = append(next, items[i])
+				}
+			}
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+//values returns the values at a path of a resource
+func (r resolver) values(resource template.Value, path string) []value {
+	nodes := r.nodes(resource, path)
+	values := make([]value, 0, len(nodes))
+
+	for _, node := range nodes {
+		values = append(values, r.resolve(node))
+	}
+
+	return values
+}
+
+//keys returns the keys of the map at a path of a resource
+func (r resolver) keys(resource template.Value, path string) []string {
+	keys := make([]string, 0)
+
+	for _, node := range r.nodes(resource, path) {
+		for _, entry := range node.Entries() {
+			keys = append(keys, entry.Key)
+		}
+	}
+
+	return keys
+}
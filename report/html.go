@@ -0,0 +1,62 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+)
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"replacement": replacementLabel,
+	"detail":      formatDetail,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Info.StackName}} ({{.Operation}})</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d1d5da; padding: 4px 10px; text-align: left; }
+th { background: #f6f8fa; }
+code { font-family: SFMono-Regular, Consolas, Menlo, monospace; }
+tr.destructive { background: #ffeef0; }
+.Add { color: #22863a; } .Modify { color: #b08800; } .Remove { color: #cb2431; font-weight: bold; }
+.warning { border-left: 4px solid #cb2431; background: #ffeef0; padding: 0.5em 1em; }
+summary { cursor: pointer; }
+</style>
+</head>
+<body>
+<h2>{{.Info.StackName}} ({{.Operation}})</h2>
+{{if .Info.ChangeSetName}}<p>Change set <code>{{.Info.ChangeSetName}}</code></p>{{end}}
+{{with .Summary}}<p><b>{{.Add}}</b> to add, <b>{{.Modify}}</b> to modify, <b>{{.Remove}}</b> to remove{{if .Destructive}} - <b>{{.Destructive}} destructive</b>{{end}}</p>{{end}}
+{{with .Destructive}}<div class="warning">
+<p>The following stateful resources will be removed or replaced:</p>
+<ul>
+{{range .}}<li><code>{{.LogicalResourceID}}</code> {{.ResourceType}}</li>
+{{end}}</ul>
+</div>{{end}}
+<table>
+<tr><th>Action</th><th>Replacement</th><th>Type</th><th>Logical ID</th></tr>
+{{range .Changes}}<tr{{if .Destructive}} class="destructive"{{end}}><td class="{{.Action}}">{{.Action}}</td><td>{{replacement .Replacement}}</td><td><code>{{.ResourceType}}</code></td><td><code>{{.LogicalResourceID}}</code></td></tr>
+{{end}}</table>
+{{range .Changes}}{{if .Details}}<details>
+<summary><code>{{.LogicalResourceID}}</code> ({{len .Details}})</summary>
+<ul>
+{{range .Details}}<li>{{detail .}}</li>
+{{end}}</ul>
+</details>
+{{end}}{{end}}</body>
+</html>
+`))
+
+//HTML renders the report as a standalone HTML page, with property details in collapsible sections
+func HTML(report Report) (string, error) {
+	var buffer bytes.Buffer
+
+	err := htmlTemplate.Execute(&buffer, report)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
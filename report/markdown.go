@@ -0,0 +1,98 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+)
+
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer("|", "\\|", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func markdownSummary(summary Summary) string {
+	formatted := fmt.Sprintf("**%d** to add, **%d** to modify, **%d** to remove", summary.Add, summary.Modify, summary.Remove)
+
+	if summary.Destructive > 0 {
+		formatted += fmt.Sprintf(" - :warning: **%d destructive**", summary.Destructive)
+	}
+
+	return formatted + "\n\n"
+}
+
+func markdownAction(change Change) string {
+	action := string(change.Action)
+
+	if change.Action == cloudformation.ChangeActionRemove {
+		return "**" + action + "**"
+	}
+
+	return action
+}
+
+func markdownTable(changes []Change) string {
+	formatted := "| Action | Replacement | Type | Logical ID |\n"
+	formatted += "| --- | --- | --- | --- |\n"
+
+	for _, change := range changes {
+		replacement := replacementLabel(change.Replacement)
+		if replacement != "" {
+			replacement = "**" + replacement + "**"
+		}
+
+		formatted += fmt.Sprintf("| %s | %s | `%s` | `%s` |\n", markdownAction(change), replacement, escapeMarkdown(change.ResourceType), escapeMarkdown(change.LogicalResourceID))
+	}
+
+	return formatted + "\n"
+}
+
+func markdownDetails(changes []Change) string {
+	var formatted string
+
+	for _, change := range changes {
+		if len(change.Details) == 0 {
+			continue
+		}
+
+		formatted += fmt.Sprintf("<details>\n<summary><code>%s</code> (%d)</summary>\n\n", escapeMarkdown(change.LogicalResourceID), len(change.Details))
+
+		for _, detail := range change.Details {
+			formatted += "- " + escapeMarkdown(formatDetail(detail)) + "\n"
+		}
+
+		formatted += "\n</details>\n\n"
+	}
+
+	if formatted == "" {
+		return ""
+	}
+
+	return "### Property details\n\n" + formatted
+}
+
+//Markdown renders the report as GitHub flavored Markdown, with property details in collapsible sections
+func Markdown(report Report) string {
+	formatted := fmt.Sprintf("## %s (%s)\n\n", escapeMarkdown(report.Info.StackName), report.Operation)
+
+	if report.Info.ChangeSetName != "" {
+		formatted += fmt.Sprintf("Change set `%s`\n\n", escapeMarkdown(report.Info.ChangeSetName))
+	}
+
+	formatted += markdownSummary(report.Summary())
+
+	if destructive := report.Destructive(); len(destructive) > 0 {
+		formatted += "> [!WARNING]\n> The following stateful resources will be removed or replaced:\n"
+
+		for _, change := range destructive {
+			formatted += fmt.Sprintf("> - `%s` %s\n", escapeMarkdown(change.LogicalResourceID), escapeMarkdown(change.ResourceType))
+		}
+
+		formatted += "\n"
+	}
+
+	formatted += markdownTable(report.Changes)
+	formatted += markdownDetails(report.Changes)
+
+	return formatted
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/data"
+)
+
+//Change is a change set entry in a report, along with the property level details of the change. Destructive changes remove or replace a stateful
+//resource, the same changes that need a typed confirmation
+type Change struct {
+	data.DisplayRow
+	Details     []cloudformation.ResourceChangeDetail
+	Destructive bool
+}
+
+//Report describes a change set for review outside of the terminal
+type Report struct {
+	Info      data.StackInfo
+	Operation cfn.StackOperation
+	Changes   []Change
+}
+
+//Summary counts the changes of each action, along with the number of destructive changes
+type Summary struct {
+	Add         int
+	Modify      int
+	Remove      int
+	Destructive int
+}
+
+func createChange(change cloudformation.Change, parent string) Change {
+	row := data.CreateDisplayRowFromChange(change, false)
+	row.LogicalResourceID = data.NestedRowKey(parent, row.LogicalResourceID)
+	row.Parent = parent
+
+	return Change{
+		DisplayRow: row,
+		Details:    change.ResourceChange.Details,
+	}
+}
+
+//New creates a report from a described change set and its nested change sets. Changes are ordered by logical ID, so nested stack changes follow
+//their nested stack. Removing or replacing resources of the stateful types is destructive
+func New(info data.StackInfo, operation cfn.StackOperation, changes []cloudformation.Change, nestedChanges map[string][]cloudformation.Change, statefulTypes []string) Report {
+	report := Report{
+		Info:      info,
+		Operation: operation,
+		Changes:   make([]Change, 0, len(changes)),
+	}
+
+	for _, change := range changes {
+		report.Changes = append(report.Changes, createChange(change, ""))
+	}
+
+	for parent, changes := range nestedChanges {
+		for _, change := range changes {
+			report.Changes = append(report.Changes, createChange(change, parent))
+		}
+	}
+
+	sort.Slice(report.Changes, func(i, j int) bool {
+		return report.Changes[i].LogicalResourceID < report.Changes[j].LogicalResourceID
+	})
+
+	displayRows := data.ChangeMap(changes, false)
+	for key, row := range data.NestedChangeMap(nestedChanges, false) {
+		displayRows[key] = row
+	}
+
+	destructive := make(map[string]bool)
+	for _, row := range data.DestructiveRows(displayRows, statefulTypes) {
+		destructive[row.LogicalResourceID] = true
+	}
+
+	for i, change := range report.Changes {
+		report.Changes[i].Destructive = destructive[change.LogicalResourceID]
+	}
+
+	return report
+}
+
+//Summary counts the report's changes
+func (report Report) Summary() Summary {
+	var summary Summary
+
+	for _, change := range report.Changes {
+		switch change.Action {
+		case cloudformation.ChangeActionAdd:
+			summary.Add++
+		case cloudformation.ChangeActionModify:
+			summary.Modify++
+		case cloudformation.ChangeActionRemove:
+			summary.Remove++
+		}
+
+		if change.Destructive {
+			summary.Destructive++
+		}
+	}
+
+	return summary
+}
+
+//Destructive returns the changes that remove or replace a stateful resource
+func (report Report) Destructive() []Change {
+	destructive := make([]Change, 0)
+
+	for _, change := range report.Changes {
+		if change.Destructive {
+			destructive = append(destructive, change)
+		}
+	}
+
+	return destructive
+}
+
+func replacementLabel(replacement cloudformation.Replacement) string {
+	switch replacement {
+	case cloudformation.ReplacementTrue:
+		return "Replace"
+	case cloudformation.ReplacementConditional:
+		return "Conditional"
+	}
+
+	return ""
+}
+
+func formatDetail(detail cloudformation.ResourceChangeDetail) string {
+	var formatted string
+
+	if detail.Target != nil {
+		formatted += string(detail.Target.Attribute)
+		if detail.Target.Name != nil {
+			formatted += "." + *detail.Target.Name
+		}
+
+		if detail.Target.RequiresRecreation != "" && detail.Target.RequiresRecreation != cloudformation.RequiresRecreationNever {
+			formatted += fmt.Sprintf(" (recreation: %s)", strings.ToLower(string(detail.Target.RequiresRecreation)))
+		}
+	}
+
+	if detail.ChangeSource != "" {
+		formatted += fmt.Sprintf(" - changed by %s", detail.ChangeSource)
+		if detail.CausingEntity != nil {
+			formatted += " " + *detail.CausingEntity
+		}
+	}
+
+	if detail.Evaluation == cloudformation.EvaluationTypeDynamic {
+		formatted += ", evaluated at deploy time"
+	}
+
+	return formatted
+}
+
+//Write renders the report to the given location. The format is picked from the extension, .html or .htm for HTML and Markdown otherwise
+func Write(location string, report Report) error {
+	var content string
+
+	switch strings.ToLower(filepath.Ext(location)) {
+	case ".html", ".htm":
+		html, err := HTML(report)
+		if err != nil {
+			return err
+		}
+
+		content = html
+	default:
+		content = Markdown(report)
+	}
+
+	return ioutil.WriteFile(location, []byte(content), 0644)
+}
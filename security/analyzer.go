@@ -0,0 +1,266 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blueseph/cirrus/template"
+)
+
+//Values that expose a resource to everyone
+const (
+	anyone       string = "*"
+	anyIPv4      string = "0.0.0.0/0"
+	anyIPv6      string = "::/0"
+	allProtocols string = "-1"
+)
+
+//principalAccount matches the account of an account ID or an IAM ARN
+var principalAccount = regexp.MustCompile(`^(\d{12})$|^arn:[\w-]+:(?:iam|sts)::(\d{12}):`)
+
+//webPorts are ports that are usually meant to be open to the internet
+var webPorts map[string]bool = map[string]bool{
+	"80":  true,
+	"443": true,
+}
+
+//publicAccessBlocks are the settings of an S3 public access block, each of which should be enabled
+var publicAccessBlocks []string = []string{
+	"BlockPublicAcls",
+	"BlockPublicPolicy",
+	"IgnorePublicAcls",
+	"RestrictPublicBuckets",
+}
+
+//resourceAnalyzer finds the exposure added by a single resource
+type resourceAnalyzer struct {
+	logicalID    string
+	resourceType string
+	accountID    string
+	line         int
+}
+
+func (a resourceAnalyzer) finding(risk Risk, category Category, format string, args ...interface{}) Finding {
+	return Finding{
+		Risk:         risk,
+		Category:     category,
+		LogicalID:    a.logicalID,
+		ResourceType: a.resourceType,
+		Message:      fmt.Sprintf(format, args...),
+		Line:         a.line,
+	}
+}
+
+//analyze finds the exposure of the resource's identity policies, trust and resource policies, security group rules and bucket settings
+func (a resourceAnalyzer) analyze(properties template.Value) []Finding {
+	findings := make([]Finding, 0)
+
+	switch a.resourceType {
+	case "AWS::IAM::Policy", "AWS::IAM::ManagedPolicy":
+		findings = append(findings, a.identityPolicy(properties.Field("PolicyDocument"))...)
+	case "AWS::IAM::Role", "AWS::IAM::User", "AWS::IAM::Group":
+		for _, policy := range properties.Field("Policies").Items() {
+			findings = append(findings, a.identityPolicy(policy.Field("PolicyDocument"))...)
+		}
+
+		for _, arn := range properties.Field("ManagedPolicyArns").Strings() {
+			if strings.HasSuffix(arn, ":policy/AdministratorAccess") {
+				findings = append(findings, a.finding(RiskHigh, CategoryWildcardAction, "Attaches AdministratorAccess, which allows every action on every resource"))
+			}
+		}
+
+		findings = append(findings, a.principals(properties.Field("AssumeRolePolicyDocument"), CategoryPublicPrincipal)...)
+	case "AWS::S3::BucketPolicy":
+		findings = append(findings, a.principals(properties.Field("PolicyDocument"), CategoryPublicBucket)...)
+	case "AWS::SQS::QueuePolicy", "AWS::SNS::TopicPolicy":
+		findings = append(findings, a.principals(properties.Field("PolicyDocument"), CategoryPublicPrincipal)...)
+	case "AWS::KMS::Key":
+		findings = append(findings, a.principals(properties.Field("KeyPolicy"), CategoryPublicPrincipal)...)
+	case "AWS::SecretsManager::ResourcePolicy":
+		findings = append(findings, a.principals(properties.Field("ResourcePolicy"), CategoryPublicPrincipal)...)
+	case "AWS::ECR::Repository":
+		findings = append(findings, a.principals(properties.Field("RepositoryPolicyText"), CategoryPublicPrincipal)...)
+	case "AWS::Lambda::Permission", "AWS::Lambda::LayerVersionPermission":
+		restricted := properties.Field("SourceArn").IsSet() || properties.Field("SourceAccount").IsSet() || properties.Field("OrganizationId").IsSet()
+		findings = append(findings, a.principal(properties.Field("Principal").String(), restricted, CategoryPublicPrincipal)...)
+	case "AWS::EC2::SecurityGroup":
+		for _, rule := range properties.Field("SecurityGroupIngress").Items() {
+			findings = append(findings, a.ingress(rule)...)
+		}
+	case "AWS::EC2::SecurityGroupIngress":
+		findings = append(findings, a.ingress(properties)...)
+	case "AWS::S3::Bucket":
+		findings = append(findings, a.bucket(properties)...)
+	}
+
+	return findings
+}
+
+//allowStatements returns the statements of a policy document that allow access. Statements whose effect is set with an intrinsic function are
+//skipped
+func allowStatements(document template.Value) []template.Value {
+	statements := make([]template.Value, 0)
+
+	for _, statement := range document.Field("Statement").Items() {
+		if statement.Field("Effect").String() == "Allow" {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements
+}
+
+func contains(values []string, expected string) bool {
+	for _, value := range values {
+		if value == expected {
+			return true
+		}
+	}
+
+	return false
+}
+
+//identityPolicy finds wildcard actions and resources in a policy attached to a role, user or group
+func (a resourceAnalyzer) identityPolicy(document template.Value) []Finding {
+	findings := make([]Finding, 0)
+
+	for _, statement := range allowStatements(document) {
+		actions := statement.Field("Action").Strings()
+		resources := statement.Field("Resource").Strings()
+		everyResource := contains(resources, anyone) || statement.Field("NotResource").IsSet()
+
+		switch {
+		case contains(actions, anyone) && everyResource:
+			findings = append(findings, a.finding(RiskHigh, CategoryWildcardAction, "Allows every action on every resource, which is administrator access"))
+			continue
+		case contains(actions, anyone):
+			findings = append(findings, a.finding(RiskHigh, CategoryWildcardAction, "Allows every action of every service"))
+		case statement.Field("NotAction").IsSet():
+			findings = append(findings, a.finding(RiskMedium, CategoryWildcardAction, "Allows every action except %s", strings.Join(statement.Field("NotAction").Strings(), ", ")))
+		}
+
+		services := make([]string, 0)
+		for _, action := range actions {
+			if strings.HasSuffix(action, ":*") {
+				services = append(services, strings.TrimSuffix(action, ":*"))
+			}
+		}
+
+		if len(services) > 0 {
+			findings = append(findings, a.finding(RiskMedium, CategoryWildcardAction, "Allows every action of %s", strings.Join(services, ", ")))
+		}
+
+		if everyResource && !contains(actions, anyone) {
+			findings = append(findings, a.finding(RiskMedium, CategoryWildcardResource, "Allows %s on every resource", strings.Join(actions, ", ")))
+		}
+	}
+
+	return findings
+}
+
+//principals finds public and cross-account principals in a trust or resource policy. Statements with conditions may limit who's allowed, so
+//public principals are a lower risk with them
+func (a resourceAnalyzer) principals(document template.Value, publicCategory Category) []Finding {
+	findings := make([]Finding, 0)
+
+	for _, statement := range allowStatements(document) {
+		restricted := statement.Field("Condition").IsSet()
+
+		if statement.Field("NotPrincipal").IsSet() {
+			findings = append(findings, a.finding(RiskHigh, publicCategory, "Allows everyone except %s", strings.Join(principalValues(statement.Field("NotPrincipal")), ", ")))
+		}
+
+		for _, value := range principalValues(statement.Field("Principal")) {
+			findings = append(findings, a.principal(value, restricted, publicCategory)...)
+		}
+	}
+
+	return findings
+}
+
+//principalValues returns the AWS principals of a Principal or NotPrincipal, which is either a string or an object keyed by principal type
+func principalValues(principal template.Value) []string {
+	if principal.IsObject() {
+		return principal.Field("AWS").Strings()
+	}
+
+	return principal.Strings()
+}
+
+//principal finds the exposure of a single principal, which is public for * and cross-account for an account other than the stack's
+func (a resourceAnalyzer) principal(principal string, restricted bool, publicCategory Category) []Finding {
+	if principal == anyone {
+		if restricted {
+			return []Finding{a.finding(RiskMedium, publicCategory, "Allows anyone, restricted by conditions")}
+		}
+
+		return []Finding{a.finding(RiskHigh, publicCategory, "Allows anyone")}
+	}
+
+	match := principalAccount.FindStringSubmatch(principal)
+	if match == nil {
+		return nil
+	}
+
+	account := match[1] + match[2]
+	if account == a.accountID {
+		return nil
+	}
+
+	return []Finding{a.finding(RiskMedium, CategoryCrossAccount, "Allows account %s", account)}
+}
+
+//ingress finds security group rules that allow traffic from the whole internet. Web ports are a lower risk, since they're usually meant to be open
+func (a resourceAnalyzer) ingress(rule template.Value) []Finding {
+	source := rule.Field("CidrIp").String()
+	if source != anyIPv4 {
+		source = rule.Field("CidrIpv6").String()
+	}
+
+	if source != anyIPv4 && source != anyIPv6 {
+		return nil
+	}
+
+	protocol := rule.Field("IpProtocol").String()
+	from := rule.Field("FromPort").String()
+	to := rule.Field("ToPort").String()
+
+	switch {
+	case protocol == allProtocols:
+		return []Finding{a.finding(RiskHigh, CategoryOpenIngress, "Allows all traffic from %s", source)}
+	case from == to && webPorts[from]:
+		return []Finding{a.finding(RiskMedium, CategoryOpenIngress, "Allows %s port %s from %s", protocol, from, source)}
+	case from == to:
+		return []Finding{a.finding(RiskHigh, CategoryOpenIngress, "Allows %s port %s from %s", protocol, from, source)}
+	}
+
+	return []Finding{a.finding(RiskHigh, CategoryOpenIngress, "Allows %s ports %s-%s from %s", protocol, from, to, source)}
+}
+
+//bucket finds public ACLs and disabled public access blocks on a bucket
+func (a resourceAnalyzer) bucket(properties template.Value) []Finding {
+	findings := make([]Finding, 0)
+
+	switch acl := properties.Field("AccessControl").String(); acl {
+	case "PublicRead", "PublicReadWrite":
+		findings = append(findings, a.finding(RiskHigh, CategoryPublicBucket, "Bucket ACL %s allows anyone", acl))
+	case "AuthenticatedRead":
+		findings = append(findings, a.finding(RiskMedium, CategoryPublicBucket, "Bucket ACL %s allows any AWS account", acl))
+	}
+
+	disabled := make([]string, 0)
+	block := properties.Field("PublicAccessBlockConfiguration")
+
+	for _, setting := range publicAccessBlocks {
+		if block.Field(setting).String() == "false" {
+			disabled = append(disabled, setting)
+		}
+	}
+
+	if len(disabled) > 0 {
+		findings = append(findings, a.finding(RiskMedium, CategoryPublicBucket, "Public access block disables %s", strings.Join(disabled, ", ")))
+	}
+
+	return findings
+}
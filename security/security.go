@@ -0,0 +1,126 @@
+package security
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/blueseph/cirrus/template"
+)
+
+//Risk is how much exposure a finding adds
+type Risk string
+
+const (
+	//RiskHigh is exposure to anyone, or every action of every service
+	RiskHigh Risk = "High"
+
+	//RiskMedium is broad exposure that's often intended, such as every action of a single service
+	RiskMedium Risk = "Medium"
+)
+
+//Category is the kind of exposure a finding describes
+type Category string
+
+const (
+	//CategoryWildcardAction is an IAM policy allowing every action, or every action of a service
+	CategoryWildcardAction Category = "Wildcard action"
+
+	//CategoryWildcardResource is an IAM policy allowing actions on every resource
+	CategoryWildcardResource Category = "Wildcard resource"
+
+	//CategoryPublicPrincipal is a trust or resource policy allowing anyone
+	CategoryPublicPrincipal Category = "Public principal"
+
+	//CategoryCrossAccount is a trust or resource policy allowing another account
+	CategoryCrossAccount Category = "Cross-account principal"
+
+	//CategoryOpenIngress is a security group allowing traffic from the whole internet
+	CategoryOpenIngress Category = "Open ingress"
+
+	//CategoryPublicBucket is a bucket readable or writable by anyone
+	CategoryPublicBucket Category = "Public bucket"
+)
+
+//Categories is the order categories are shown in
+var Categories []Category = []Category{
+	CategoryPublicPrincipal,
+	CategoryPublicBucket,
+	CategoryOpenIngress,
+	CategoryCrossAccount,
+	CategoryWildcardAction,
+	CategoryWildcardResource,
+}
+
+//Finding is IAM or network exposure added by a resource in a template
+type Finding struct {
+	Risk         Risk
+	Category     Category
+	LogicalID    string
+	ResourceType string
+	Message      string
+	Line         int
+}
+
+//Analyze parses a JSON or YAML template and returns the IAM and network exposure its resources add, ordered by risk and logical ID. Principals
+//of the given account aren't cross-account. Values set with intrinsic functions can't be resolved before deploying, so they're skipped
+func Analyze(contents []byte, accountID string) ([]Finding, error) {
+	parsed, err := template.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0)
+
+	for _, resource := range parsed.Resources {
+		analyzer := resourceAnalyzer{
+			logicalID:    resource.LogicalID,
+			resourceType: resource.Type,
+			accountID:    accountID,
+			line:         resource.Position.Line,
+		}
+
+		findings = append(findings, analyzer.analyze(resource.Properties)...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Risk != findings[j].Risk {
+			return findings[i].Risk == RiskHigh
+		}
+
+		return findings[i].LogicalID < findings[j].LogicalID
+	})
+
+	return findings, nil
+}
+
+//AccountFromARN returns the account ID of an ARN, such as a stack ID
+func AccountFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 5 {
+		return ""
+	}
+
+	return parts[4]
+}
+
+//Count returns the number of findings of each risk
+func Count(findings []Finding) map[Risk]int {
+	counts := make(map[Risk]int)
+
+	for _, finding := range findings {
+		counts[finding.Risk]++
+	}
+
+	return counts
+}
+
+//GroupByCategory returns the findings of each category
+func GroupByCategory(findings []Finding) map[Category][]Finding {
+	groups := make(map[Category][]Finding)
+
+	for _, finding := range findings {
+		groups[finding.Category] = append(groups[finding.Category], finding)
+	}
+
+	return groups
+}
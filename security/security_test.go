@@ -0,0 +1,209 @@
+package security
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//account is the account stacks in the tests are deployed to
+const account string = "111111111111"
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name:     "every action on every resource",
+			template: "Policy:\n  Type: AWS::IAM::Policy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Action: '*'\n          Resource: '*'\n",
+			want:     []string{"High Wildcard action: Allows every action on every resource, which is administrator access"},
+		},
+		{
+			name:     "every action on a single resource",
+			template: "Policy:\n  Type: AWS::IAM::Policy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Action: '*'\n          Resource: arn:aws:s3:::bucket\n",
+			want:     []string{"High Wildcard action: Allows every action of every service"},
+		},
+		{
+			name:     "denied wildcards",
+			template: "Policy:\n  Type: AWS::IAM::Policy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Deny\n          Action: '*'\n          Resource: '*'\n",
+			want:     []string{},
+		},
+		{
+			name:     "every action except some",
+			template: "Policy:\n  Type: AWS::IAM::ManagedPolicy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          NotAction: [iam:*, organizations:*]\n          Resource: arn:aws:s3:::bucket\n",
+			want:     []string{"Medium Wildcard action: Allows every action except iam:*, organizations:*"},
+		},
+		{
+			name:     "every resource except some",
+			template: "Policy:\n  Type: AWS::IAM::Policy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Action: s3:GetObject\n          NotResource: arn:aws:s3:::secrets/*\n",
+			want:     []string{"Medium Wildcard resource: Allows s3:GetObject on every resource"},
+		},
+		{
+			name:     "every action of a service",
+			template: "Policy:\n  Type: AWS::IAM::Policy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Action: [s3:*, sqs:SendMessage]\n          Resource: arn:aws:s3:::bucket\n",
+			want:     []string{"Medium Wildcard action: Allows every action of s3"},
+		},
+		{
+			name:     "inline policy of a role on every resource",
+			template: "Role:\n  Type: AWS::IAM::Role\n  Properties:\n    Policies:\n      - PolicyName: read\n        PolicyDocument:\n          Statement:\n            Effect: Allow\n            Action: s3:GetObject\n            Resource: '*'\n",
+			want:     []string{"Medium Wildcard resource: Allows s3:GetObject on every resource"},
+		},
+		{
+			name:     "AdministratorAccess",
+			template: "Role:\n  Type: AWS::IAM::Role\n  Properties:\n    ManagedPolicyArns: [arn:aws:iam::aws:policy/AdministratorAccess]\n",
+			want:     []string{"High Wildcard action: Attaches AdministratorAccess, which allows every action on every resource"},
+		},
+		{
+			name:     "public principal",
+			template: "Policy:\n  Type: AWS::SQS::QueuePolicy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Principal: '*'\n          Action: sqs:SendMessage\n",
+			want:     []string{"High Public principal: Allows anyone"},
+		},
+		{
+			name:     "public AWS principal",
+			template: "Policy:\n  Type: AWS::SNS::TopicPolicy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Principal:\n            AWS: '*'\n          Action: sns:Publish\n",
+			want:     []string{"High Public principal: Allows anyone"},
+		},
+		{
+			name:     "public principal restricted by conditions",
+			template: "Policy:\n  Type: AWS::SQS::QueuePolicy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Principal: '*'\n          Action: sqs:SendMessage\n          Condition:\n            ArnEquals:\n              aws:SourceArn: arn:aws:sns:us-east-1:111111111111:topic\n",
+			want:     []string{"Medium Public principal: Allows anyone, restricted by conditions"},
+		},
+		{
+			name:     "public bucket policy",
+			template: "Policy:\n  Type: AWS::S3::BucketPolicy\n  Properties:\n    PolicyDocument:\n      Statement:\n        - Effect: Allow\n          Principal: '*'\n          Action: s3:GetObject\n",
+			want:     []string{"High Public bucket: Allows anyone"},
+		},
+		{
+			name:     "everyone except a principal",
+			template: "Key:\n  Type: AWS::KMS::Key\n  Properties:\n    KeyPolicy:\n      Statement:\n        - Effect: Allow\n          NotPrincipal:\n            AWS: arn:aws:iam::111111111111:root\n          Action: kms:Decrypt\n",
+			want:     []string{"High Public principal: Allows everyone except arn:aws:iam::111111111111:root"},
+		},
+		{
+			name:     "same account principal",
+			template: "Role:\n  Type: AWS::IAM::Role\n  Properties:\n    AssumeRolePolicyDocument:\n      Statement:\n        - Effect: Allow\n          Principal:\n            AWS: [arn:aws:iam::111111111111:root, '111111111111']\n          Action: sts:AssumeRole\n",
+			want:     []string{},
+		},
+		{
+			name:     "cross-account principal",
+			template: "Role:\n  Type: AWS::IAM::Role\n  Properties:\n    AssumeRolePolicyDocument:\n      Statement:\n        - Effect: Allow\n          Principal:\n            AWS: arn:aws:iam::222222222222:role/deployer\n          Action: sts:AssumeRole\n",
+			want:     []string{"Medium Cross-account principal: Allows account 222222222222"},
+		},
+		{
+			name:     "service principal",
+			template: "Role:\n  Type: AWS::IAM::Role\n  Properties:\n    AssumeRolePolicyDocument:\n      Statement:\n        - Effect: Allow\n          Principal:\n            Service: lambda.amazonaws.com\n          Action: sts:AssumeRole\n",
+			want:     []string{},
+		},
+		{
+			name:     "public Lambda permission",
+			template: "Permission:\n  Type: AWS::Lambda::Permission\n  Properties:\n    Principal: '*'\n    Action: lambda:InvokeFunction\n",
+			want:     []string{"High Public principal: Allows anyone"},
+		},
+		{
+			name:     "Lambda permission restricted to a source",
+			template: "Permission:\n  Type: AWS::Lambda::Permission\n  Properties:\n    Principal: '*'\n    SourceArn: arn:aws:s3:::bucket\n    Action: lambda:InvokeFunction\n",
+			want:     []string{"Medium Public principal: Allows anyone, restricted by conditions"},
+		},
+		{
+			name:     "web port open to IPv4",
+			template: "Group:\n  Type: AWS::EC2::SecurityGroup\n  Properties:\n    SecurityGroupIngress:\n      - CidrIp: 0.0.0.0/0\n        IpProtocol: tcp\n        FromPort: 443\n        ToPort: 443\n",
+			want:     []string{"Medium Open ingress: Allows tcp port 443 from 0.0.0.0/0"},
+		},
+		{
+			name:     "web port open to IPv6",
+			template: "Ingress:\n  Type: AWS::EC2::SecurityGroupIngress\n  Properties:\n    CidrIpv6: ::/0\n    IpProtocol: tcp\n    FromPort: 80\n    ToPort: 80\n",
+			want:     []string{"Medium Open ingress: Allows tcp port 80 from ::/0"},
+		},
+		{
+			name:     "other port open",
+			template: "Group:\n  Type: AWS::EC2::SecurityGroup\n  Properties:\n    SecurityGroupIngress:\n      - CidrIp: 0.0.0.0/0\n        IpProtocol: tcp\n        FromPort: 22\n        ToPort: 22\n",
+			want:     []string{"High Open ingress: Allows tcp port 22 from 0.0.0.0/0"},
+		},
+		{
+			name:     "port range open",
+			template: "Group:\n  Type: AWS::EC2::SecurityGroup\n  Properties:\n    SecurityGroupIngress:\n      - CidrIpv6: ::/0\n        IpProtocol: udp\n        FromPort: 1000\n        ToPort: 2000\n",
+			want:     []string{"High Open ingress: Allows udp ports 1000-2000 from ::/0"},
+		},
+		{
+			name:     "all traffic open",
+			template: "Group:\n  Type: AWS::EC2::SecurityGroup\n  Properties:\n    SecurityGroupIngress:\n      - CidrIp: 0.0.0.0/0\n        IpProtocol: '-1'\n",
+			want:     []string{"High Open ingress: Allows all traffic from 0.0.0.0/0"},
+		},
+		{
+			name:     "private network",
+			template: "Group:\n  Type: AWS::EC2::SecurityGroup\n  Properties:\n    SecurityGroupIngress:\n      - CidrIp: 10.0.0.0/8\n        IpProtocol: '-1'\n",
+			want:     []string{},
+		},
+		{
+			name:     "public read bucket",
+			template: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    AccessControl: PublicRead\n",
+			want:     []string{"High Public bucket: Bucket ACL PublicRead allows anyone"},
+		},
+		{
+			name:     "authenticated read bucket",
+			template: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    AccessControl: AuthenticatedRead\n",
+			want:     []string{"Medium Public bucket: Bucket ACL AuthenticatedRead allows any AWS account"},
+		},
+		{
+			name:     "disabled public access blocks",
+			template: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    PublicAccessBlockConfiguration:\n      BlockPublicAcls: true\n      BlockPublicPolicy: false\n      IgnorePublicAcls: true\n      RestrictPublicBuckets: false\n",
+			want:     []string{"Medium Public bucket: Public access block disables BlockPublicPolicy, RestrictPublicBuckets"},
+		},
+		{
+			name:     "values set with intrinsic functions",
+			template: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    AccessControl: !Ref Acl\n",
+			want:     []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings, err := Analyze([]byte("Resources:\n"+indentLines(test.template)), account)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0)
+			for _, finding := range findings {
+				got = append(got, string(finding.Risk)+" "+string(finding.Category)+": "+finding.Message)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeOrder(t *testing.T) {
+	template := "Resources:\n" +
+		"  Zebra:\n    Type: AWS::S3::Bucket\n    Properties:\n      AccessControl: PublicRead\n" +
+		"  Apple:\n    Type: AWS::S3::Bucket\n    Properties:\n      AccessControl: AuthenticatedRead\n" +
+		"  Mango:\n    Type: AWS::S3::Bucket\n    Properties:\n      AccessControl: PublicReadWrite\n"
+
+	findings, err := Analyze([]byte(template), account)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, 0)
+	for _, finding := range findings {
+		got = append(got, finding.LogicalID)
+	}
+
+	if want := []string{"Mango", "Zebra", "Apple"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+//indentLines indents each line of a template section's contents
+func indentLines(contents string) string {
+	indented := ""
+
+	for _, line := range strings.Split(strings.TrimSuffix(contents, "\n"), "\n") {
+		indented += "  " + line + "\n"
+	}
+
+	return indented
+}
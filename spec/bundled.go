@@ -0,0 +1,1277 @@
+package spec
+
+//bundled is the resource specification bundled with cirrus. It's a subset of the CloudFormation resource specification, in the same format, covering
+//commonly used resource types. It isn't a published version, so it has none. Resource types it doesn't cover aren't validated until the
+//specification is updated with cirrus spec update
+var bundled string = `{
+  "PropertyTypes": {
+    "AWS::DynamoDB::Table.AttributeDefinition": {
+      "Properties": {
+        "AttributeName": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "AttributeType": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "AttributeType"
+          }
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.ContributorInsightsSpecification": {
+      "Properties": {
+        "Enabled": {
+          "PrimitiveType": "Boolean",
+          "Required": true
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.GlobalSecondaryIndex": {
+      "Properties": {
+        "ContributorInsightsSpecification": {
+          "Type": "ContributorInsightsSpecification"
+        },
+        "IndexName": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "KeySchema": {
+          "ItemType": "KeySchema",
+          "Required": true,
+          "Type": "List"
+        },
+        "Projection": {
+          "Required": true,
+          "Type": "Projection"
+        },
+        "ProvisionedThroughput": {
+          "Type": "ProvisionedThroughput"
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.KeySchema": {
+      "Properties": {
+        "AttributeName": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "KeyType": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "KeyType"
+          }
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.KinesisStreamSpecification": {
+      "Properties": {
+        "StreamArn": {
+          "PrimitiveType": "String",
+          "Required": true
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.LocalSecondaryIndex": {
+      "Properties": {
+        "IndexName": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "KeySchema": {
+          "ItemType": "KeySchema",
+          "Required": true,
+          "Type": "List"
+        },
+        "Projection": {
+          "Required": true,
+          "Type": "Projection"
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.PointInTimeRecoverySpecification": {
+      "Properties": {
+        "PointInTimeRecoveryEnabled": {
+          "PrimitiveType": "Boolean"
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.Projection": {
+      "Properties": {
+        "NonKeyAttributes": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "ProjectionType": {
+          "PrimitiveType": "String",
+          "Value": {
+            "ValueType": "ProjectionType"
+          }
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.ProvisionedThroughput": {
+      "Properties": {
+        "ReadCapacityUnits": {
+          "PrimitiveType": "Long",
+          "Required": true
+        },
+        "WriteCapacityUnits": {
+          "PrimitiveType": "Long",
+          "Required": true
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.SSESpecification": {
+      "Properties": {
+        "KMSMasterKeyId": {
+          "PrimitiveType": "String"
+        },
+        "SSEEnabled": {
+          "PrimitiveType": "Boolean",
+          "Required": true
+        },
+        "SSEType": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.StreamSpecification": {
+      "Properties": {
+        "StreamViewType": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "StreamViewType"
+          }
+        }
+      }
+    },
+    "AWS::DynamoDB::Table.TimeToLiveSpecification": {
+      "Properties": {
+        "AttributeName": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "Enabled": {
+          "PrimitiveType": "Boolean",
+          "Required": true
+        }
+      }
+    },
+    "AWS::EC2::SecurityGroup.Egress": {
+      "Properties": {
+        "CidrIp": {
+          "PrimitiveType": "String"
+        },
+        "CidrIpv6": {
+          "PrimitiveType": "String"
+        },
+        "Description": {
+          "PrimitiveType": "String"
+        },
+        "DestinationPrefixListId": {
+          "PrimitiveType": "String"
+        },
+        "DestinationSecurityGroupId": {
+          "PrimitiveType": "String"
+        },
+        "FromPort": {
+          "PrimitiveType": "Integer"
+        },
+        "IpProtocol": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "ToPort": {
+          "PrimitiveType": "Integer"
+        }
+      }
+    },
+    "AWS::EC2::SecurityGroup.Ingress": {
+      "Properties": {
+        "CidrIp": {
+          "PrimitiveType": "String"
+        },
+        "CidrIpv6": {
+          "PrimitiveType": "String"
+        },
+        "Description": {
+          "PrimitiveType": "String"
+        },
+        "FromPort": {
+          "PrimitiveType": "Integer"
+        },
+        "IpProtocol": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "SourcePrefixListId": {
+          "PrimitiveType": "String"
+        },
+        "SourceSecurityGroupId": {
+          "PrimitiveType": "String"
+        },
+        "SourceSecurityGroupName": {
+          "PrimitiveType": "String"
+        },
+        "SourceSecurityGroupOwnerId": {
+          "PrimitiveType": "String"
+        },
+        "ToPort": {
+          "PrimitiveType": "Integer"
+        }
+      }
+    },
+    "AWS::IAM::Role.Policy": {
+      "Properties": {
+        "PolicyDocument": {
+          "PrimitiveType": "Json",
+          "Required": true
+        },
+        "PolicyName": {
+          "PrimitiveType": "String",
+          "Required": true
+        }
+      }
+    },
+    "AWS::Lambda::Function.Code": {
+      "Properties": {
+        "ImageUri": {
+          "PrimitiveType": "String"
+        },
+        "S3Bucket": {
+          "PrimitiveType": "String"
+        },
+        "S3Key": {
+          "PrimitiveType": "String"
+        },
+        "S3ObjectVersion": {
+          "PrimitiveType": "String"
+        },
+        "ZipFile": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::Lambda::Function.DeadLetterConfig": {
+      "Properties": {
+        "TargetArn": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::Lambda::Function.Environment": {
+      "Properties": {
+        "Variables": {
+          "PrimitiveItemType": "String",
+          "Type": "Map"
+        }
+      }
+    },
+    "AWS::Lambda::Function.EphemeralStorage": {
+      "Properties": {
+        "Size": {
+          "PrimitiveType": "Integer",
+          "Required": true
+        }
+      }
+    },
+    "AWS::Lambda::Function.FileSystemConfig": {
+      "Properties": {
+        "Arn": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "LocalMountPath": {
+          "PrimitiveType": "String",
+          "Required": true
+        }
+      }
+    },
+    "AWS::Lambda::Function.ImageConfig": {
+      "Properties": {
+        "Command": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "EntryPoint": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "WorkingDirectory": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::Lambda::Function.TracingConfig": {
+      "Properties": {
+        "Mode": {
+          "PrimitiveType": "String",
+          "Value": {
+            "ValueType": "TracingMode"
+          }
+        }
+      }
+    },
+    "AWS::Lambda::Function.VpcConfig": {
+      "Properties": {
+        "SecurityGroupIds": {
+          "PrimitiveItemType": "String",
+          "Required": true,
+          "Type": "List"
+        },
+        "SubnetIds": {
+          "PrimitiveItemType": "String",
+          "Required": true,
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::S3::Bucket.BucketEncryption": {
+      "Properties": {
+        "ServerSideEncryptionConfiguration": {
+          "ItemType": "ServerSideEncryptionRule",
+          "Required": true,
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::S3::Bucket.CorsConfiguration": {
+      "Properties": {
+        "CorsRules": {
+          "ItemType": "CorsRule",
+          "Required": true,
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::S3::Bucket.CorsRule": {
+      "Properties": {
+        "AllowedHeaders": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "AllowedMethods": {
+          "PrimitiveItemType": "String",
+          "Required": true,
+          "Type": "List",
+          "Value": {
+            "ValueType": "CorsRuleAllowedMethods"
+          }
+        },
+        "AllowedOrigins": {
+          "PrimitiveItemType": "String",
+          "Required": true,
+          "Type": "List"
+        },
+        "ExposedHeaders": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "Id": {
+          "PrimitiveType": "String"
+        },
+        "MaxAge": {
+          "PrimitiveType": "Integer"
+        }
+      }
+    },
+    "AWS::S3::Bucket.LifecycleConfiguration": {
+      "Properties": {
+        "Rules": {
+          "ItemType": "Rule",
+          "Required": true,
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::S3::Bucket.LoggingConfiguration": {
+      "Properties": {
+        "DestinationBucketName": {
+          "PrimitiveType": "String"
+        },
+        "LogFilePrefix": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::S3::Bucket.PublicAccessBlockConfiguration": {
+      "Properties": {
+        "BlockPublicAcls": {
+          "PrimitiveType": "Boolean"
+        },
+        "BlockPublicPolicy": {
+          "PrimitiveType": "Boolean"
+        },
+        "IgnorePublicAcls": {
+          "PrimitiveType": "Boolean"
+        },
+        "RestrictPublicBuckets": {
+          "PrimitiveType": "Boolean"
+        }
+      }
+    },
+    "AWS::S3::Bucket.ServerSideEncryptionByDefault": {
+      "Properties": {
+        "KMSMasterKeyID": {
+          "PrimitiveType": "String"
+        },
+        "SSEAlgorithm": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "SSEAlgorithm"
+          }
+        }
+      }
+    },
+    "AWS::S3::Bucket.ServerSideEncryptionRule": {
+      "Properties": {
+        "BucketKeyEnabled": {
+          "PrimitiveType": "Boolean"
+        },
+        "ServerSideEncryptionByDefault": {
+          "Type": "ServerSideEncryptionByDefault"
+        }
+      }
+    },
+    "AWS::S3::Bucket.VersioningConfiguration": {
+      "Properties": {
+        "Status": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "BucketVersioningStatus"
+          }
+        }
+      }
+    },
+    "AWS::S3::Bucket.WebsiteConfiguration": {
+      "Properties": {
+        "ErrorDocument": {
+          "PrimitiveType": "String"
+        },
+        "IndexDocument": {
+          "PrimitiveType": "String"
+        },
+        "RedirectAllRequestsTo": {
+          "Type": "RedirectAllRequestsTo"
+        },
+        "RoutingRules": {
+          "ItemType": "RoutingRule",
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::SNS::Topic.Subscription": {
+      "Properties": {
+        "Endpoint": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "Protocol": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "SNSProtocol"
+          }
+        }
+      }
+    },
+    "Tag": {
+      "Properties": {
+        "Key": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "Value": {
+          "PrimitiveType": "String",
+          "Required": true
+        }
+      }
+    }
+  },
+  "ResourceTypes": {
+    "AWS::CloudFormation::Stack": {
+      "Properties": {
+        "NotificationARNs": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "Parameters": {
+          "PrimitiveItemType": "String",
+          "Type": "Map"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        },
+        "TemplateURL": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "TimeoutInMinutes": {
+          "PrimitiveType": "Integer"
+        }
+      }
+    },
+    "AWS::DynamoDB::Table": {
+      "Properties": {
+        "AttributeDefinitions": {
+          "ItemType": "AttributeDefinition",
+          "Type": "List"
+        },
+        "BillingMode": {
+          "PrimitiveType": "String",
+          "Value": {
+            "ValueType": "BillingMode"
+          }
+        },
+        "ContributorInsightsSpecification": {
+          "Type": "ContributorInsightsSpecification"
+        },
+        "GlobalSecondaryIndexes": {
+          "ItemType": "GlobalSecondaryIndex",
+          "Type": "List"
+        },
+        "KeySchema": {
+          "ItemType": "KeySchema",
+          "Required": true,
+          "Type": "List"
+        },
+        "KinesisStreamSpecification": {
+          "Type": "KinesisStreamSpecification"
+        },
+        "LocalSecondaryIndexes": {
+          "ItemType": "LocalSecondaryIndex",
+          "Type": "List"
+        },
+        "PointInTimeRecoverySpecification": {
+          "Type": "PointInTimeRecoverySpecification"
+        },
+        "ProvisionedThroughput": {
+          "Type": "ProvisionedThroughput"
+        },
+        "SSESpecification": {
+          "Type": "SSESpecification"
+        },
+        "StreamSpecification": {
+          "Type": "StreamSpecification"
+        },
+        "TableClass": {
+          "PrimitiveType": "String"
+        },
+        "TableName": {
+          "PrimitiveType": "String"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        },
+        "TimeToLiveSpecification": {
+          "Type": "TimeToLiveSpecification"
+        }
+      }
+    },
+    "AWS::EC2::SecurityGroup": {
+      "Properties": {
+        "GroupDescription": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "GroupName": {
+          "PrimitiveType": "String"
+        },
+        "SecurityGroupEgress": {
+          "ItemType": "Egress",
+          "Type": "List"
+        },
+        "SecurityGroupIngress": {
+          "ItemType": "Ingress",
+          "Type": "List"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        },
+        "VpcId": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::IAM::ManagedPolicy": {
+      "Properties": {
+        "Description": {
+          "PrimitiveType": "String"
+        },
+        "Groups": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "ManagedPolicyName": {
+          "PrimitiveType": "String"
+        },
+        "Path": {
+          "PrimitiveType": "String"
+        },
+        "PolicyDocument": {
+          "PrimitiveType": "Json",
+          "Required": true
+        },
+        "Roles": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "Users": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::IAM::Policy": {
+      "Properties": {
+        "Groups": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "PolicyDocument": {
+          "PrimitiveType": "Json",
+          "Required": true
+        },
+        "PolicyName": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "Roles": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "Users": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::IAM::Role": {
+      "Properties": {
+        "AssumeRolePolicyDocument": {
+          "PrimitiveType": "Json",
+          "Required": true
+        },
+        "Description": {
+          "PrimitiveType": "String"
+        },
+        "ManagedPolicyArns": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "MaxSessionDuration": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "RoleMaxSessionDuration"
+          }
+        },
+        "Path": {
+          "PrimitiveType": "String"
+        },
+        "PermissionsBoundary": {
+          "PrimitiveType": "String"
+        },
+        "Policies": {
+          "ItemType": "Policy",
+          "Type": "List"
+        },
+        "RoleName": {
+          "PrimitiveType": "String"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::KMS::Alias": {
+      "Properties": {
+        "AliasName": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "KeyAliasName"
+          }
+        },
+        "TargetKeyId": {
+          "PrimitiveType": "String",
+          "Required": true
+        }
+      }
+    },
+    "AWS::KMS::Key": {
+      "Properties": {
+        "Description": {
+          "PrimitiveType": "String"
+        },
+        "EnableKeyRotation": {
+          "PrimitiveType": "Boolean"
+        },
+        "Enabled": {
+          "PrimitiveType": "Boolean"
+        },
+        "KeyPolicy": {
+          "PrimitiveType": "Json",
+          "Required": true
+        },
+        "KeySpec": {
+          "PrimitiveType": "String"
+        },
+        "KeyUsage": {
+          "PrimitiveType": "String",
+          "Value": {
+            "ValueType": "KeyUsage"
+          }
+        },
+        "MultiRegion": {
+          "PrimitiveType": "Boolean"
+        },
+        "PendingWindowInDays": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "KeyPendingWindowInDays"
+          }
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::Lambda::Function": {
+      "Properties": {
+        "Architectures": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "Code": {
+          "Required": true,
+          "Type": "Code"
+        },
+        "CodeSigningConfigArn": {
+          "PrimitiveType": "String"
+        },
+        "DeadLetterConfig": {
+          "Type": "DeadLetterConfig"
+        },
+        "Description": {
+          "PrimitiveType": "String"
+        },
+        "Environment": {
+          "Type": "Environment"
+        },
+        "EphemeralStorage": {
+          "Type": "EphemeralStorage"
+        },
+        "FileSystemConfigs": {
+          "ItemType": "FileSystemConfig",
+          "Type": "List"
+        },
+        "FunctionName": {
+          "PrimitiveType": "String"
+        },
+        "Handler": {
+          "PrimitiveType": "String"
+        },
+        "ImageConfig": {
+          "Type": "ImageConfig"
+        },
+        "KmsKeyArn": {
+          "PrimitiveType": "String"
+        },
+        "Layers": {
+          "PrimitiveItemType": "String",
+          "Type": "List"
+        },
+        "MemorySize": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "LambdaMemorySize"
+          }
+        },
+        "PackageType": {
+          "PrimitiveType": "String"
+        },
+        "ReservedConcurrentExecutions": {
+          "PrimitiveType": "Integer"
+        },
+        "Role": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "Runtime": {
+          "PrimitiveType": "String"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        },
+        "Timeout": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "LambdaTimeout"
+          }
+        },
+        "TracingConfig": {
+          "Type": "TracingConfig"
+        },
+        "VpcConfig": {
+          "Type": "VpcConfig"
+        }
+      }
+    },
+    "AWS::Lambda::Permission": {
+      "Properties": {
+        "Action": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "EventSourceToken": {
+          "PrimitiveType": "String"
+        },
+        "FunctionName": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "Principal": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "SourceAccount": {
+          "PrimitiveType": "String"
+        },
+        "SourceArn": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::Logs::LogGroup": {
+      "Properties": {
+        "KmsKeyId": {
+          "PrimitiveType": "String"
+        },
+        "LogGroupName": {
+          "PrimitiveType": "String"
+        },
+        "RetentionInDays": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "LogGroupRetentionInDays"
+          }
+        }
+      }
+    },
+    "AWS::S3::Bucket": {
+      "Properties": {
+        "AccelerateConfiguration": {
+          "Type": "AccelerateConfiguration"
+        },
+        "AccessControl": {
+          "PrimitiveType": "String",
+          "Value": {
+            "ValueType": "BucketAccessControl"
+          }
+        },
+        "AnalyticsConfigurations": {
+          "ItemType": "AnalyticsConfiguration",
+          "Type": "List"
+        },
+        "BucketEncryption": {
+          "Type": "BucketEncryption"
+        },
+        "BucketName": {
+          "PrimitiveType": "String",
+          "Value": {
+            "ValueType": "BucketName"
+          }
+        },
+        "CorsConfiguration": {
+          "Type": "CorsConfiguration"
+        },
+        "IntelligentTieringConfigurations": {
+          "ItemType": "IntelligentTieringConfiguration",
+          "Type": "List"
+        },
+        "InventoryConfigurations": {
+          "ItemType": "InventoryConfiguration",
+          "Type": "List"
+        },
+        "LifecycleConfiguration": {
+          "Type": "LifecycleConfiguration"
+        },
+        "LoggingConfiguration": {
+          "Type": "LoggingConfiguration"
+        },
+        "MetricsConfigurations": {
+          "ItemType": "MetricsConfiguration",
+          "Type": "List"
+        },
+        "NotificationConfiguration": {
+          "Type": "NotificationConfiguration"
+        },
+        "ObjectLockConfiguration": {
+          "Type": "ObjectLockConfiguration"
+        },
+        "ObjectLockEnabled": {
+          "PrimitiveType": "Boolean"
+        },
+        "OwnershipControls": {
+          "Type": "OwnershipControls"
+        },
+        "PublicAccessBlockConfiguration": {
+          "Type": "PublicAccessBlockConfiguration"
+        },
+        "ReplicationConfiguration": {
+          "Type": "ReplicationConfiguration"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        },
+        "VersioningConfiguration": {
+          "Type": "VersioningConfiguration"
+        },
+        "WebsiteConfiguration": {
+          "Type": "WebsiteConfiguration"
+        }
+      }
+    },
+    "AWS::S3::BucketPolicy": {
+      "Properties": {
+        "Bucket": {
+          "PrimitiveType": "String",
+          "Required": true
+        },
+        "PolicyDocument": {
+          "PrimitiveType": "Json",
+          "Required": true
+        }
+      }
+    },
+    "AWS::SNS::Subscription": {
+      "Properties": {
+        "DeliveryPolicy": {
+          "PrimitiveType": "Json"
+        },
+        "Endpoint": {
+          "PrimitiveType": "String"
+        },
+        "FilterPolicy": {
+          "PrimitiveType": "Json"
+        },
+        "Protocol": {
+          "PrimitiveType": "String",
+          "Required": true,
+          "Value": {
+            "ValueType": "SNSProtocol"
+          }
+        },
+        "RawMessageDelivery": {
+          "PrimitiveType": "Boolean"
+        },
+        "RedrivePolicy": {
+          "PrimitiveType": "Json"
+        },
+        "Region": {
+          "PrimitiveType": "String"
+        },
+        "SubscriptionRoleArn": {
+          "PrimitiveType": "String"
+        },
+        "TopicArn": {
+          "PrimitiveType": "String",
+          "Required": true
+        }
+      }
+    },
+    "AWS::SNS::Topic": {
+      "Properties": {
+        "ContentBasedDeduplication": {
+          "PrimitiveType": "Boolean"
+        },
+        "DisplayName": {
+          "PrimitiveType": "String"
+        },
+        "FifoTopic": {
+          "PrimitiveType": "Boolean"
+        },
+        "KmsMasterKeyId": {
+          "PrimitiveType": "String"
+        },
+        "Subscription": {
+          "ItemType": "Subscription",
+          "Type": "List"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        },
+        "TopicName": {
+          "PrimitiveType": "String"
+        }
+      }
+    },
+    "AWS::SNS::TopicPolicy": {
+      "Properties": {
+        "PolicyDocument": {
+          "PrimitiveType": "Json",
+          "Required": true
+        },
+        "Topics": {
+          "PrimitiveItemType": "String",
+          "Required": true,
+          "Type": "List"
+        }
+      }
+    },
+    "AWS::SQS::Queue": {
+      "Properties": {
+        "ContentBasedDeduplication": {
+          "PrimitiveType": "Boolean"
+        },
+        "DeduplicationScope": {
+          "PrimitiveType": "String"
+        },
+        "DelaySeconds": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "QueueDelaySeconds"
+          }
+        },
+        "FifoQueue": {
+          "PrimitiveType": "Boolean"
+        },
+        "FifoThroughputLimit": {
+          "PrimitiveType": "String"
+        },
+        "KmsDataKeyReusePeriodSeconds": {
+          "PrimitiveType": "Integer"
+        },
+        "KmsMasterKeyId": {
+          "PrimitiveType": "String"
+        },
+        "MaximumMessageSize": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "QueueMaximumMessageSize"
+          }
+        },
+        "MessageRetentionPeriod": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "QueueMessageRetentionPeriod"
+          }
+        },
+        "QueueName": {
+          "PrimitiveType": "String"
+        },
+        "ReceiveMessageWaitTimeSeconds": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "QueueReceiveMessageWaitTimeSeconds"
+          }
+        },
+        "RedriveAllowPolicy": {
+          "PrimitiveType": "Json"
+        },
+        "RedrivePolicy": {
+          "PrimitiveType": "Json"
+        },
+        "Tags": {
+          "ItemType": "Tag",
+          "Type": "List"
+        },
+        "VisibilityTimeout": {
+          "PrimitiveType": "Integer",
+          "Value": {
+            "ValueType": "QueueVisibilityTimeout"
+          }
+        }
+      }
+    },
+    "AWS::SQS::QueuePolicy": {
+      "Properties": {
+        "PolicyDocument": {
+          "PrimitiveType": "Json",
+          "Required": true
+        },
+        "Queues": {
+          "PrimitiveItemType": "String",
+          "Required": true,
+          "Type": "List"
+        }
+      }
+    }
+  },
+  "ValueTypes": {
+    "AttributeType": {
+      "AllowedValues": [
+        "B",
+        "N",
+        "S"
+      ]
+    },
+    "BillingMode": {
+      "AllowedValues": [
+        "PAY_PER_REQUEST",
+        "PROVISIONED"
+      ]
+    },
+    "BucketAccessControl": {
+      "AllowedValues": [
+        "AuthenticatedRead",
+        "AwsExecRead",
+        "BucketOwnerFullControl",
+        "BucketOwnerRead",
+        "LogDeliveryWrite",
+        "Private",
+        "PublicRead",
+        "PublicReadWrite"
+      ]
+    },
+    "BucketName": {
+      "AllowedPatternRegex": "^[a-z0-9][a-z0-9.-]*[a-z0-9]$",
+      "StringMax": 63,
+      "StringMin": 3
+    },
+    "BucketVersioningStatus": {
+      "AllowedValues": [
+        "Enabled",
+        "Suspended"
+      ]
+    },
+    "CorsRuleAllowedMethods": {
+      "AllowedValues": [
+        "DELETE",
+        "GET",
+        "HEAD",
+        "POST",
+        "PUT"
+      ]
+    },
+    "KeyAliasName": {
+      "AllowedPatternRegex": "^alias/[a-zA-Z0-9/_-]+$",
+      "StringMax": 256,
+      "StringMin": 1
+    },
+    "KeyPendingWindowInDays": {
+      "NumberMax": 30,
+      "NumberMin": 7
+    },
+    "KeyType": {
+      "AllowedValues": [
+        "HASH",
+        "RANGE"
+      ]
+    },
+    "KeyUsage": {
+      "AllowedValues": [
+        "ENCRYPT_DECRYPT",
+        "SIGN_VERIFY"
+      ]
+    },
+    "LambdaMemorySize": {
+      "NumberMax": 10240,
+      "NumberMin": 128
+    },
+    "LambdaTimeout": {
+      "NumberMax": 900,
+      "NumberMin": 1
+    },
+    "LogGroupRetentionInDays": {
+      "AllowedValues": [
+        "1",
+        "3",
+        "5",
+        "7",
+        "14",
+        "30",
+        "60",
+        "90",
+        "120",
+        "150",
+        "180",
+        "365",
+        "400",
+        "545",
+        "731",
+        "1827",
+        "3653"
+      ]
+    },
+    "ProjectionType": {
+      "AllowedValues": [
+        "ALL",
+        "INCLUDE",
+        "KEYS_ONLY"
+      ]
+    },
+    "QueueDelaySeconds": {
+      "NumberMax": 900,
+      "NumberMin": 0
+    },
+    "QueueMaximumMessageSize": {
+      "NumberMax": 262144,
+      "NumberMin": 1024
+    },
+    "QueueMessageRetentionPeriod": {
+      "NumberMax": 1209600,
+      "NumberMin": 60
+    },
+    "QueueReceiveMessageWaitTimeSeconds": {
+      "NumberMax": 20,
+      "NumberMin": 0
+    },
+    "QueueVisibilityTimeout": {
+      "NumberMax": 43200,
+      "NumberMin": 0
+    },
+    "RoleMaxSessionDuration": {
+      "NumberMax": 43200,
+      "NumberMin": 3600
+    },
+    "SNSProtocol": {
+      "AllowedValues": [
+        "application",
+        "email",
+        "email-json",
+        "firehose",
+        "http",
+        "https",
+        "lambda",
+        "sms",
+        "sqs"
+      ]
+    },
+    "SSEAlgorithm": {
+      "AllowedValues": [
+        "AES256",
+        "aws:kms"
+      ]
+    },
+    "StreamViewType": {
+      "AllowedValues": [
+        "KEYS_ONLY",
+        "NEW_AND_OLD_IMAGES",
+        "NEW_IMAGE",
+        "OLD_IMAGE"
+      ]
+    },
+    "TracingMode": {
+      "AllowedValues": [
+        "Active",
+        "PassThrough"
+      ]
+    }
+  }
+}
+`
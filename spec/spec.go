@@ -0,0 +1,177 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/data"
+)
+
+const (
+	//specificationFile is the name of an updated specification in the user's config directory
+	specificationFile string = "specification.json"
+
+	//SourceBundled is the source of the specification bundled with cirrus
+	SourceBundled string = "bundled"
+)
+
+//ValueType constrains the values of a property
+type ValueType struct {
+	AllowedValues       []string `json:"AllowedValues"`
+	AllowedPatternRegex string   `json:"AllowedPatternRegex"`
+	StringMin           *float64 `json:"StringMin"`
+	StringMax           *float64 `json:"StringMax"`
+	NumberMin           *float64 `json:"NumberMin"`
+	NumberMax           *float64 `json:"NumberMax"`
+}
+
+//Value points a property at the value type that constrains it
+type Value struct {
+	ValueType string `json:"ValueType"`
+}
+
+//Property describes a property of a resource or property type. Properties have a primitive type, or a type that's a list, a map or a property
+//type. Lists and maps have a primitive item type or an item type that's a property type
+type Property struct {
+	Documentation     string `json:"Documentation"`
+	Required          bool   `json:"Required"`
+	PrimitiveType     string `json:"PrimitiveType"`
+	Type              string `json:"Type"`
+	PrimitiveItemType string `json:"PrimitiveItemType"`
+	ItemType          string `json:"ItemType"`
+	UpdateType        string `json:"UpdateType"`
+	Value             *Value `json:"Value"`
+}
+
+//PropertyType describes a structured property, such as the versioning configuration of a bucket
+type PropertyType struct {
+	Documentation string              `json:"Documentation"`
+	Properties    map[string]Property `json:"Properties"`
+}
+
+//ResourceType describes the properties of a resource type
+type ResourceType struct {
+	Documentation string              `json:"Documentation"`
+	Properties    map[string]Property `json:"Properties"`
+}
+
+//Specification is a CloudFormation resource specification. Source is bundled, or the location of an updated specification
+type Specification struct {
+	ResourceSpecificationVersion string                  `json:"ResourceSpecificationVersion"`
+	PropertyTypes                map[string]PropertyType `json:"PropertyTypes"`
+	ResourceTypes                map[string]ResourceType `json:"ResourceTypes"`
+	ValueTypes                   map[string]ValueType    `json:"ValueTypes"`
+
+	Source string `json:"-"`
+}
+
+//Parse parses a resource specification in the format AWS publishes
+func Parse(contents []byte) (*Specification, error) {
+	var specification Specification
+
+	if err := json.Unmarshal(contents, &specification); err != nil {
+		return nil, err
+	}
+
+	if len(specification.ResourceTypes) == 0 {
+		return nil, errors.New("specification has no resource types")
+	}
+
+	return &specification, nil
+}
+
+//Get returns the updated specification in the user's config directory, or the bundled specification without one
+func Get() (*Specification, error) {
+	location, err := data.ConfigLocation(specificationFile)
+	if err != nil {
+		return Bundled()
+	}
+
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return Bundled()
+	}
+
+	specification, err := Parse(contents)
+	if err != nil {
+		return nil, errors.New(colors.Error(fmt.Sprintf("Unable to load resource specification %s. %s. Update it again, or delete it to use the bundled specification", location, err)))
+	}
+
+	specification.Source = location
+
+	return specification, nil
+}
+
+//Bundled returns the specification bundled with cirrus
+func Bundled() (*Specification, error) {
+	specification, err := Parse([]byte(bundled))
+	if err != nil {
+		return nil, err
+	}
+
+	specification.Source = SourceBundled
+
+	return specification, nil
+}
+
+//Update replaces the specification with the one at the given location, such as a CloudFormationResourceSpecification.json downloaded from AWS.
+//It's checked before it's saved to the user's config directory
+func Update(location string) (*Specification, error) {
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	specification, err := Parse(contents)
+	if err != nil {
+		return nil, errors.New(colors.Error(fmt.Sprintf("%s isn't a resource specification. %s", location, err)))
+	}
+
+	destination, err := data.ConfigLocation(specificationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(destination, contents, 0644); err != nil {
+		return nil, err
+	}
+
+	specification.Source = destination
+
+	return specification, nil
+}
+
+//Reset removes the updated specification, so the bundled specification is used again
+func Reset() error {
+	location, err := data.ConfigLocation(specificationFile)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(location)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+//PropertyType returns a property type of a resource type. Shared property types such as Tag aren't prefixed with a resource type
+func (specification *Specification) PropertyType(resourceType string, name string) (PropertyType, bool) {
+	if propertyType, ok := specification.PropertyTypes[resourceType+"."+name]; ok {
+		return propertyType, true
+	}
+
+	propertyType, ok := specification.PropertyTypes[name]
+
+	return propertyType, ok
+}
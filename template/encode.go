@@ -0,0 +1,204 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//indent is the indentation of serialized templates
+const indent string = "  "
+
+//Marshal serializes a template as YAML or JSON. YAML keeps short form intrinsics and comments as they're written. JSON has no short form, so
+//they're written in full, such as !GetAtt Bucket.Arn as {"Fn::GetAtt": ["Bucket", "Arn"]}
+func (t *Template) Marshal(format Format) ([]byte, error) {
+	if format == FormatJSON {
+		var buffer bytes.Buffer
+
+		err := writeJSON(&buffer, t.document.Content[0], "")
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.WriteString("\n")
+
+		return buffer.Bytes(), nil
+	}
+
+	document := t.document
+	if t.Format == FormatJSON {
+		document = blockStyle(document)
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(len(indent))
+
+	err := encoder.Encode(document)
+	if err != nil {
+		return nil, err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+//blockStyle returns a copy of a node without its style, so a JSON template is written as block style YAML rather than quoted flow style
+func blockStyle(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Style = 0
+	copied.Content = make([]*yaml.Node, 0, len(node.Content))
+
+	for _, child := range node.Content {
+		copied.Content = append(copied.Content, blockStyle(child))
+	}
+
+	return &copied
+}
+
+//writeJSON writes a node as indented JSON, keeping the order of keys
+func writeJSON(buffer *bytes.Buffer, node *yaml.Node, prefix string) error {
+	value := newValue(node)
+
+	if tag := shortTag(value.node); tag != "" {
+		intrinsic, _ := value.Intrinsic()
+
+		argument := intrinsic.Argument.node
+		if tag == "!GetAtt" && argument.Kind == yaml.ScalarNode {
+			argument = splitGetAtt(argument)
+		}
+
+		buffer.WriteString("{\n" + prefix + indent)
+		writeString(buffer, intrinsic.Name)
+		buffer.WriteString(": ")
+
+		err := writeJSON(buffer, argument, prefix+indent)
+		if err != nil {
+			return err
+		}
+
+		buffer.WriteString("\n" + prefix + "}")
+
+		return nil
+	}
+
+	switch value.node.Kind {
+	case yaml.MappingNode:
+		if len(value.node.Content) == 0 {
+			buffer.WriteString("{}")
+			return nil
+		}
+
+		buffer.WriteString("{")
+
+		for i := 0; i+1 < len(value.node.Content); i += 2 {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+
+			buffer.WriteString("\n" + prefix + indent)
+			writeString(buffer, value.node.Content[i].Value)
+			buffer.WriteString(": ")
+
+			err := writeJSON(buffer, value.node.Content[i+1], prefix+indent)
+			if err != nil {
+				return err
+			}
+		}
+
+		buffer.WriteString("\n" + prefix + "}")
+	case yaml.SequenceNode:
+		if len(value.node.Content) == 0 {
+			buffer.WriteString("[]")
+			return nil
+		}
+
+		buffer.WriteString("[")
+
+		for i, item := range value.node.Content {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+
+			buffer.WriteString("\n" + prefix + indent)
+
+			err := writeJSON(buffer, item, prefix+indent)
+			if err != nil {
+				return err
+			}
+		}
+
+		buffer.WriteString("\n" + prefix + "]")
+	case yaml.ScalarNode:
+		writeScalar(buffer, value.node)
+	default:
+		return Error{Position: value.Position(), Message: fmt.Sprintf("%s can't be written as JSON", value.node.Tag)}
+	}
+
+	return nil
+}
+
+//splitGetAtt splits the argument of a short form !GetAtt Bucket.Arn into the logical ID and attribute, which is the only form JSON allows.
+//Attributes can have dots, so only the first one splits
+func splitGetAtt(argument *yaml.Node) *yaml.Node {
+	parts := strings.SplitN(argument.Value, ".", 2)
+
+	split := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: argument.Line, Column: argument.Column}
+	for _, part := range parts {
+		split.Content = append(split.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part})
+	}
+
+	return split
+}
+
+//writeScalar writes a string, number, boolean or null. YAML numbers that aren't valid JSON, such as 0x1F, are written as their JSON equivalent,
+//and numbers JSON can't represent, such as .inf, as strings
+func writeScalar(buffer *bytes.Buffer, node *yaml.Node) {
+	switch node.ShortTag() {
+	case "!!null":
+		buffer.WriteString("null")
+		return
+	case "!!bool":
+		buffer.WriteString(strings.ToLower(node.Value))
+		return
+	case "!!int", "!!float":
+		if json.Valid([]byte(node.Value)) {
+			buffer.WriteString(node.Value)
+			return
+		}
+
+		number := strings.ReplaceAll(node.Value, "_", "")
+		if integer, err := strconv.ParseInt(number, 0, 64); err == nil {
+			buffer.WriteString(strconv.FormatInt(integer, 10))
+			return
+		}
+
+		if float, err := strconv.ParseFloat(number, 64); err == nil && !math.IsInf(float, 0) && !math.IsNaN(float) {
+			buffer.WriteString(strconv.FormatFloat(float, 'g', -1, 64))
+			return
+		}
+	}
+
+	writeString(buffer, node.Value)
+}
+
+//writeString writes a quoted JSON string. HTML characters aren't escaped, since templates aren't embedded in HTML
+func writeString(buffer *bytes.Buffer, value string) {
+	var quoted bytes.Buffer
+
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+
+	buffer.Write(bytes.TrimSuffix(quoted.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,133 @@
+package template
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+//source is a YAML template with short form intrinsics, YAML-only numbers and comments
+const source string = `# Bucket for the site
+Resources:
+  Bucket: # kept when serialized
+    Type: AWS::S3::Bucket
+    Properties:
+      BucketName: !Sub '${AWS::StackName}-bucket'
+      Owner: !Ref Owner
+      Arn: !GetAtt Stack.Outputs.Arn
+      Names: !Join [',', [a, b]]
+      Hex: 0x1F
+      Infinity: .inf
+      Enabled: true
+      Empty: null
+`
+
+//properties returns the properties of the bucket in a JSON template
+func properties(t *testing.T, contents []byte) map[string]interface{} {
+	var decoded struct {
+		Resources map[string]struct {
+			Properties map[string]interface{}
+		}
+	}
+
+	if err := json.Unmarshal(contents, &decoded); err != nil {
+		t.Fatalf("JSON isn't valid: %s\n%s", err, contents)
+	}
+
+	return decoded.Resources["Bucket"].Properties
+}
+
+func TestMarshalYAMLKeepsSource(t *testing.T) {
+	parsed, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	marshalled, err := parsed.Marshal(FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(marshalled) != source {
+		t.Errorf("got\n%s\nwant\n%s", marshalled, source)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	parsed, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	marshalled, err := parsed.Marshal(FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := properties(t, marshalled)
+
+	tests := []struct {
+		property string
+		want     interface{}
+	}{
+		{property: "BucketName", want: map[string]interface{}{"Fn::Sub": "${AWS::StackName}-bucket"}},
+		{property: "Owner", want: map[string]interface{}{"Ref": "Owner"}},
+		{property: "Arn", want: map[string]interface{}{"Fn::GetAtt": []interface{}{"Stack", "Outputs.Arn"}}},
+		{property: "Names", want: map[string]interface{}{"Fn::Join": []interface{}{",", []interface{}{"a", "b"}}}},
+		{property: "Hex", want: float64(31)},
+		{property: "Infinity", want: ".inf"},
+		{property: "Enabled", want: true},
+		{property: "Empty", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.property, func(t *testing.T) {
+			if !reflect.DeepEqual(got[test.property], test.want) {
+				t.Errorf("got %#v, want %#v", got[test.property], test.want)
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	parsed, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := parsed.Marshal(FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromJSON, err := Parse(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	converted, err := fromJSON.Marshal(FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromYAML, err := Parse(converted)
+	if err != nil {
+		t.Fatalf("YAML from JSON can't be parsed: %s\n%s", err, converted)
+	}
+
+	second, err := fromYAML.Marshal(FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(first) != string(second) {
+		t.Errorf("JSON changed after a round trip through YAML\ngot\n%s\nwant\n%s", second, first)
+	}
+
+	resource, _ := fromYAML.Resource("Bucket")
+
+	intrinsic, ok := resource.Properties.Field("Arn").Intrinsic()
+	if !ok || intrinsic.Name != "Fn::GetAtt" || !reflect.DeepEqual(intrinsic.Argument.Strings(), []string{"Stack", "Outputs.Arn"}) {
+		t.Errorf("got Arn %+v, want Fn::GetAtt of Stack and Outputs.Arn", intrinsic)
+	}
+}
@@ -0,0 +1,221 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+)
+
+//listPrefix is the prefix of parameter types that take a comma separated list, such as List<AWS::EC2::Subnet::Id>
+const listPrefix string = "List<"
+
+//awsTypePatterns are the formats of AWS-specific parameter types that can be checked without calling AWS. Availability zones include Local Zones,
+//such as us-west-2-lax-1a
+var awsTypePatterns map[string]*regexp.Regexp = map[string]*regexp.Regexp{
+	"AWS::EC2::AvailabilityZone::Name":   regexp.MustCompile(`^[a-z]{2}(-gov)?-[a-z]+-\d(-[a-z]+-\d)?[a-z]$`),
+	"AWS::EC2::Image::Id":                regexp.MustCompile(`^ami-[0-9a-f]{8}([0-9a-f]{9})?$`),
+	"AWS::EC2::Instance::Id":             regexp.MustCompile(`^i-[0-9a-f]{8}([0-9a-f]{9})?$`),
+	"AWS::EC2::KeyPair::KeyName":         regexp.MustCompile(`^.+$`),
+	"AWS::EC2::SecurityGroup::GroupName": regexp.MustCompile(`^.+$`),
+	"AWS::EC2::SecurityGroup::Id":        regexp.MustCompile(`^sg-[0-9a-f]{8}([0-9a-f]{9})?$`),
+	"AWS::EC2::Subnet::Id":               regexp.MustCompile(`^subnet-[0-9a-f]{8}([0-9a-f]{9})?$`),
+	"AWS::EC2::Volume::Id":               regexp.MustCompile(`^vol-[0-9a-f]{8}([0-9a-f]{9})?$`),
+	"AWS::EC2::VPC::Id":                  regexp.MustCompile(`^vpc-[0-9a-f]{8}([0-9a-f]{9})?$`),
+	"AWS::Route53::HostedZone::Id":       regexp.MustCompile(`^Z[A-Z0-9]+$`),
+}
+
+//awsTypeExamples are examples of the formats of AWS-specific parameter types, shown when a value doesn't match
+var awsTypeExamples map[string]string = map[string]string{
+	"AWS::EC2::AvailabilityZone::Name":   "us-east-1a",
+	"AWS::EC2::Image::Id":                "ami-0123456789abcdef0",
+	"AWS::EC2::Instance::Id":             "i-0123456789abcdef0",
+	"AWS::EC2::KeyPair::KeyName":         "a key pair name",
+	"AWS::EC2::SecurityGroup::GroupName": "a security group name",
+	"AWS::EC2::SecurityGroup::Id":        "sg-0123456789abcdef0",
+	"AWS::EC2::Subnet::Id":               "subnet-0123456789abcdef0",
+	"AWS::EC2::Volume::Id":               "vol-0123456789abcdef0",
+	"AWS::EC2::VPC::Id":                  "vpc-0123456789abcdef0",
+	"AWS::Route53::HostedZone::Id":       "Z0123456789ABCDEFGHIJ",
+}
+
+//ParameterProblem is a parameter value the template won't accept, or a parameter it doesn't have. The parameter's Description and
+//ConstraintDescription are kept to explain what's expected
+type ParameterProblem struct {
+	Name                  string
+	Message               string
+	Description           string
+	ConstraintDescription string
+	Position
+}
+
+//ValidateParameters checks parameter values against the template's Parameters section, the way CloudFormation does when creating a change set.
+//Every parameter without a default needs a value, every value needs a parameter, and values and defaults need to match their parameter's type
+//and constraints. Values of parameters using their previous value aren't known, so they aren't checked. Problems are ordered as the template's
+//parameters are, followed by unknown parameters
+func (t *Template) ValidateParameters(values []cloudformation.Parameter) []ParameterProblem {
+	problems := make([]ParameterProblem, 0)
+	supplied := make(map[string]cloudformation.Parameter)
+
+	for _, value := range values {
+		if value.ParameterKey != nil {
+			supplied[*value.ParameterKey] = value
+		}
+	}
+
+	for _, parameter := range t.Parameters {
+		value, ok := supplied[parameter.Name]
+
+		switch {
+		case ok && value.UsePreviousValue != nil && *value.UsePreviousValue:
+			continue
+		case ok && value.ParameterValue != nil:
+			problems = append(problems, parameter.validate(*value.ParameterValue, "")...)
+		case parameter.Default.IsSet():
+			if literal, isLiteral := parameter.Default.Literal(); isLiteral {
+				problems = append(problems, parameter.validate(literal, "default ")...)
+			}
+		default:
+			problems = append(problems, parameter.problem("needs a value, since it has no default"))
+		}
+	}
+
+	unknown := make([]string, 0)
+	for name := range supplied {
+		if _, ok := t.Parameter(name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		problems = append(problems, ParameterProblem{Name: name, Message: "isn't a parameter of the template"})
+	}
+
+	return problems
+}
+
+func (p Parameter) problem(format string, args ...interface{}) ParameterProblem {
+	return ParameterProblem{
+		Name:                  p.Name,
+		Message:               fmt.Sprintf(format, args...),
+		Description:           p.Description,
+		ConstraintDescription: p.ConstraintDescription,
+		Position:              p.Position,
+	}
+}
+
+//shown returns how a value is shown in a problem. NoEcho values are secrets, so they're masked
+func (p Parameter) shown(value string) string {
+	if p.NoEcho {
+		return "****"
+	}
+
+	return fmt.Sprintf("%q", value)
+}
+
+//validate checks a value against the parameter's type and constraints. List types check each item. The kind describes the value in problems,
+//such as "default "
+func (p Parameter) validate(value string, kind string) []ParameterProblem {
+	problems := make([]ParameterProblem, 0)
+
+	itemType := p.Type
+	items := []string{value}
+
+	switch {
+	case p.Type == "CommaDelimitedList":
+		itemType = "String"
+		items = splitList(value)
+	case strings.HasPrefix(p.Type, listPrefix) && strings.HasSuffix(p.Type, ">"):
+		itemType = strings.TrimSuffix(strings.TrimPrefix(p.Type, listPrefix), ">")
+		items = splitList(value)
+	}
+
+	if p.Type == "String" {
+		problems = append(problems, p.validateLength(value, kind)...)
+	}
+
+	for _, item := range items {
+		problems = append(problems, p.validateItem(item, itemType, kind)...)
+	}
+
+	return problems
+}
+
+//splitList splits a comma separated list, trimming the spaces around items
+func splitList(value string) []string {
+	items := strings.Split(value, ",")
+
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+
+	return items
+}
+
+//validateLength checks the length of a string value. CloudFormation only applies MinLength and MaxLength to String parameters, not to lists
+func (p Parameter) validateLength(value string, kind string) []ParameterProblem {
+	problems := make([]ParameterProblem, 0)
+
+	if p.MinLength != nil && len(value) < *p.MinLength {
+		problems = append(problems, p.problem("%svalue must be at least %d characters", kind, *p.MinLength))
+	}
+
+	if p.MaxLength != nil && len(value) > *p.MaxLength {
+		problems = append(problems, p.problem("%svalue must be at most %d characters", kind, *p.MaxLength))
+	}
+
+	return problems
+}
+
+//validateItem checks a single value, or an item of a list, against its type, allowed values and pattern. Types that need AWS to check, such as
+//SSM parameters, aren't checked
+func (p Parameter) validateItem(item string, itemType string, kind string) []ParameterProblem {
+	problems := make([]ParameterProblem, 0)
+
+	if itemType == "Number" {
+		number, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			return append(problems, p.problem("%svalue %s isn't a number", kind, p.shown(item)))
+		}
+
+		if p.MinValue != nil && number < *p.MinValue {
+			problems = append(problems, p.problem("%svalue %s must be at least %v", kind, p.shown(item), *p.MinValue))
+		}
+
+		if p.MaxValue != nil && number > *p.MaxValue {
+			problems = append(problems, p.problem("%svalue %s must be at most %v", kind, p.shown(item), *p.MaxValue))
+		}
+	}
+
+	if pattern, ok := awsTypePatterns[itemType]; ok && !pattern.MatchString(item) {
+		problems = append(problems, p.problem("%svalue %s isn't a valid %s, such as %s", kind, p.shown(item), itemType, awsTypeExamples[itemType]))
+	}
+
+	if len(p.AllowedValues) > 0 && !contains(p.AllowedValues, item) {
+		problems = append(problems, p.problem("%svalue %s must be one of %s", kind, p.shown(item), strings.Join(p.AllowedValues, ", ")))
+	}
+
+	if p.AllowedPattern != "" {
+		pattern, err := regexp.Compile("^(?:" + p.AllowedPattern + ")$")
+		if err == nil && !pattern.MatchString(item) {
+			problems = append(problems, p.problem("%svalue %s doesn't match the pattern %s", kind, p.shown(item), p.AllowedPattern))
+		}
+	}
+
+	return problems
+}
+
+func contains(values []string, expected string) bool {
+	for _, value := range values {
+		if value == expected {
+			return true
+		}
+	}
+
+	return false
+}
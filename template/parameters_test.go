@@ -0,0 +1,157 @@
+package template
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+)
+
+//value returns a parameter value
+func value(key string, parameterValue string) cloudformation.Parameter {
+	return cloudformation.Parameter{ParameterKey: aws.String(key), ParameterValue: aws.String(parameterValue)}
+}
+
+//previous returns a parameter using its previous value
+func previous(key string) cloudformation.Parameter {
+	return cloudformation.Parameter{ParameterKey: aws.String(key), UsePreviousValue: aws.Bool(true)}
+}
+
+func TestValidateParameters(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters string
+		values     []cloudformation.Parameter
+		want       []string
+	}{
+		{
+			name:       "value for every parameter",
+			parameters: "Name:\n  Type: String\n",
+			values:     []cloudformation.Parameter{value("Name", "site")},
+			want:       []string{},
+		},
+		{
+			name:       "missing value without a default",
+			parameters: "Name:\n  Type: String\n",
+			want:       []string{"Name needs a value, since it has no default"},
+		},
+		{
+			name:       "missing value with a default",
+			parameters: "Name:\n  Type: String\n  Default: site\n",
+			want:       []string{},
+		},
+		{
+			name:       "default that breaks a constraint",
+			parameters: "Name:\n  Type: String\n  Default: site\n  AllowedValues: [a, b]\n",
+			want:       []string{`Name default value "site" must be one of a, b`},
+		},
+		{
+			name:       "default set with an intrinsic function",
+			parameters: "Name:\n  Type: String\n  Default: !Ref AWS::Region\n  AllowedValues: [a, b]\n",
+			want:       []string{},
+		},
+		{
+			name:       "previous value isn't checked",
+			parameters: "Size:\n  Type: Number\n  MaxValue: 10\n",
+			values:     []cloudformation.Parameter{previous("Size")},
+			want:       []string{},
+		},
+		{
+			name:       "unknown parameters",
+			parameters: "Name:\n  Type: String\n",
+			values:     []cloudformation.Parameter{value("Name", "site"), value("Zone", "a"), value("Extra", "b")},
+			want:       []string{"Extra isn't a parameter of the template", "Zone isn't a parameter of the template"},
+		},
+		{
+			name:       "number out of range",
+			parameters: "Size:\n  Type: Number\n  MinValue: 1\n  MaxValue: 10\n",
+			values:     []cloudformation.Parameter{value("Size", "11")},
+			want:       []string{`Size value "11" must be at most 10`},
+		},
+		{
+			name:       "string too long",
+			parameters: "Name:\n  Type: String\n  MaxLength: 3\n",
+			values:     []cloudformation.Parameter{value("Name", "site")},
+			want:       []string{"Name value must be at most 3 characters"},
+		},
+		{
+			name:       "string that doesn't match the pattern",
+			parameters: "Name:\n  Type: String\n  AllowedPattern: '[a-z]+'\n",
+			values:     []cloudformation.Parameter{value("Name", "Site1")},
+			want:       []string{`Name value "Site1" doesn't match the pattern [a-z]+`},
+		},
+		{
+			name:       "comma delimited list checks each item",
+			parameters: "Names:\n  Type: CommaDelimitedList\n  AllowedValues: [a, b]\n",
+			values:     []cloudformation.Parameter{value("Names", "a, c")},
+			want:       []string{`Names value "c" must be one of a, b`},
+		},
+		{
+			name:       "comma delimited list isn't length checked",
+			parameters: "Names:\n  Type: CommaDelimitedList\n  MaxLength: 3\n",
+			values:     []cloudformation.Parameter{value("Names", "a,b,c")},
+			want:       []string{},
+		},
+		{
+			name:       "list of numbers",
+			parameters: "Sizes:\n  Type: List<Number>\n",
+			values:     []cloudformation.Parameter{value("Sizes", "1,two")},
+			want:       []string{`Sizes value "two" isn't a number`},
+		},
+		{
+			name:       "list of subnets",
+			parameters: "Subnets:\n  Type: List<AWS::EC2::Subnet::Id>\n",
+			values:     []cloudformation.Parameter{value("Subnets", "subnet-0123abcd,vpc-0123abcd")},
+			want:       []string{`Subnets value "vpc-0123abcd" isn't a valid AWS::EC2::Subnet::Id, such as subnet-0123456789abcdef0`},
+		},
+		{
+			name:       "availability zones including a Local Zone",
+			parameters: "Zones:\n  Type: List<AWS::EC2::AvailabilityZone::Name>\n",
+			values:     []cloudformation.Parameter{value("Zones", "us-east-1a,us-gov-west-1b,us-west-2-lax-1a")},
+			want:       []string{},
+		},
+		{
+			name:       "invalid availability zone",
+			parameters: "Zone:\n  Type: AWS::EC2::AvailabilityZone::Name\n",
+			values:     []cloudformation.Parameter{value("Zone", "us-east-1")},
+			want:       []string{`Zone value "us-east-1" isn't a valid AWS::EC2::AvailabilityZone::Name, such as us-east-1a`},
+		},
+		{
+			name:       "NoEcho values are masked",
+			parameters: "Password:\n  Type: String\n  NoEcho: true\n  AllowedPattern: '[a-z]+'\n",
+			values:     []cloudformation.Parameter{value("Password", "Secret1")},
+			want:       []string{"Password value **** doesn't match the pattern [a-z]+"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := Parse([]byte("Parameters:\n" + indentLines(test.parameters) + "Resources: {}\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]string, 0)
+			for _, problem := range parsed.ValidateParameters(test.values) {
+				got = append(got, problem.Name+" "+problem.Message)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+//indentLines indents each line of a section's contents
+func indentLines(contents string) string {
+	indented := ""
+
+	for _, line := range strings.Split(strings.TrimSuffix(contents, "\n"), "\n") {
+		indented += indent + line + "\n"
+	}
+
+	return indented
+}
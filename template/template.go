@@ -0,0 +1,348 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//Format is the format a template is written in
+type Format string
+
+const (
+	//FormatYAML is a YAML template, which may use short form intrinsics such as !Ref
+	FormatYAML Format = "yaml"
+
+	//FormatJSON is a JSON template
+	FormatJSON Format = "json"
+)
+
+//Parameter is an input to a template, along with the constraints CloudFormation checks its value against
+type Parameter struct {
+	Name                  string
+	Type                  string
+	Description           string
+	Default               Value
+	AllowedValues         []string
+	AllowedPattern        string
+	MinLength             *int
+	MaxLength             *int
+	MinValue              *float64
+	MaxValue              *float64
+	NoEcho                bool
+	ConstraintDescription string
+	Position
+}
+
+//Mapping is a named lookup table used by Fn::FindInMap
+type Mapping struct {
+	Name  string
+	Value Value
+	Position
+}
+
+//Condition is a named condition resources and outputs are created on
+type Condition struct {
+	Name  string
+	Value Value
+	Position
+}
+
+//Resource is a resource in a template, along with its attributes. Definition is the whole resource, for reading attributes as they're written
+type Resource struct {
+	LogicalID           string
+	Type                string
+	Properties          Value
+	DependsOn           []string
+	Condition           string
+	DeletionPolicy      Value
+	UpdateReplacePolicy Value
+	Metadata            Value
+	Definition          Value
+	Position
+}
+
+//Output is a value a stack returns, which can be exported for other stacks to import. Definition is the whole output
+type Output struct {
+	Name        string
+	Description string
+	Value       Value
+	ExportName  Value
+	Condition   string
+	Definition  Value
+	Position
+}
+
+//objectSections are the sections of a template that are objects of named entries
+var objectSections map[string]bool = map[string]bool{
+	"Parameters": true,
+	"Mappings":   true,
+	"Conditions": true,
+	"Resources":  true,
+	"Outputs":    true,
+}
+
+//Template is a parsed JSON or YAML template. Sections are kept in the order they're written. The typed model is read from the parsed document,
+//which is what's serialized, so changes made through values are kept when serializing
+type Template struct {
+	FormatVersion string
+	Description   string
+	Transform     []string
+	Parameters    []Parameter
+	Mappings      []Mapping
+	Conditions    []Condition
+	Resources     []Resource
+	Outputs       []Output
+	Format        Format
+
+	document *yaml.Node
+}
+
+//Error is a template that can't be parsed, along with where the problem is
+type Error struct {
+	Position
+	Message string
+}
+
+func (err Error) Error() string {
+	if err.Line == 0 {
+		return err.Message
+	}
+
+	return fmt.Sprintf("line %d: %s", err.Line, err.Message)
+}
+
+//ReadFile reads and parses a template file
+func ReadFile(location string) (*Template, error) {
+	contents, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := Parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+
+	return parsed, nil
+}
+
+//Parse parses a JSON or YAML template. Short form intrinsics are kept as they're written
+func Parse(contents []byte) (*Template, error) {
+	var document yaml.Node
+
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, Error{Message: "template isn't a JSON or YAML object"}
+	}
+
+	parsed := &Template{
+		Format:   formatOf(contents),
+		document: &document,
+	}
+
+	root := newValue(document.Content[0])
+
+	for _, section := range entries(root.node) {
+		if objectSections[section.Key] && section.Value.node.Kind != yaml.MappingNode && !section.Value.IsNull() {
+			return nil, Error{Position: section.Position, Message: fmt.Sprintf("%s must be an object", section.Key)}
+		}
+
+		switch section.Key {
+		case "AWSTemplateFormatVersion":
+			parsed.FormatVersion = section.Value.String()
+		case "Description":
+			parsed.Description = section.Value.String()
+		case "Transform":
+			parsed.Transform = section.Value.Strings()
+		case "Parameters":
+			for _, entry := range entries(section.Value.node) {
+				parsed.Parameters = append(parsed.Parameters, newParameter(entry))
+			}
+		case "Mappings":
+			for _, entry := range entries(section.Value.node) {
+				parsed.Mappings = append(parsed.Mappings, Mapping{Name: entry.Key, Value: entry.Value, Position: entry.Position})
+			}
+		case "Conditions":
+			for _, entry := range entries(section.Value.node) {
+				parsed.Conditions = append(parsed.Conditions, Condition{Name: entry.Key, Value: entry.Value, Position: entry.Position})
+			}
+		case "Resources":
+			for _, entry := range entries(section.Value.node) {
+				parsed.Resources = append(parsed.Resources, newResource(entry))
+			}
+		case "Outputs":
+			for _, entry := range entries(section.Value.node) {
+				parsed.Outputs = append(parsed.Outputs, newOutput(entry))
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+//formatOf determines if a template is JSON, which always starts with an object, or YAML
+func formatOf(contents []byte) Format {
+	if bytes.HasPrefix(bytes.TrimSpace(contents), []byte("{")) {
+		return FormatJSON
+	}
+
+	return FormatYAML
+}
+
+//entries returns the key value pairs of a mapping node, in order and including duplicates
+func entries(node *yaml.Node) []Entry {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	found := make([]Entry, 0, len(node.Content)/2)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+
+		found = append(found, Entry{
+			Key:      key.Value,
+			Value:    newValue(node.Content[i+1]),
+			Position: Position{Line: key.Line, Column: key.Column},
+		})
+	}
+
+	return found
+}
+
+func newParameter(entry Entry) Parameter {
+	definition := entry.Value
+
+	return Parameter{
+		Name:                  entry.Key,
+		Type:                  definition.Field("Type").String(),
+		Description:           definition.Field("Description").String(),
+		Default:               definition.Field("Default"),
+		AllowedValues:         definition.Field("AllowedValues").Strings(),
+		AllowedPattern:        definition.Field("AllowedPattern").String(),
+		MinLength:             intField(definition, "MinLength"),
+		MaxLength:             intField(definition, "MaxLength"),
+		MinValue:              floatField(definition, "MinValue"),
+		MaxValue:              floatField(definition, "MaxValue"),
+		NoEcho:                strings.EqualFold(definition.Field("NoEcho").String(), "true"),
+		ConstraintDescription: definition.Field("ConstraintDescription").String(),
+		Position:              entry.Position,
+	}
+}
+
+func newResource(entry Entry) Resource {
+	definition := entry.Value
+
+	return Resource{
+		LogicalID:           entry.Key,
+		Type:                definition.Field("Type").String(),
+		Properties:          definition.Field("Properties"),
+		DependsOn:           definition.Field("DependsOn").Strings(),
+		Condition:           definition.Field("Condition").String(),
+		DeletionPolicy:      definition.Field("DeletionPolicy"),
+		UpdateReplacePolicy: definition.Field("UpdateReplacePolicy"),
+		Metadata:            definition.Field("Metadata"),
+		Definition:          definition,
+		Position:            entry.Position,
+	}
+}
+
+func newOutput(entry Entry) Output {
+	definition := entry.Value
+
+	return Output{
+		Name:        entry.Key,
+		Description: definition.Field("Description").String(),
+		Value:       definition.Field("Value"),
+		ExportName:  definition.Field("Export").Field("Name"),
+		Condition:   definition.Field("Condition").String(),
+		Definition:  definition,
+		Position:    entry.Position,
+	}
+}
+
+//intField returns the integer value of a key, or nil when it isn't set to an integer
+func intField(value Value, key string) *int {
+	number, err := strconv.Atoi(value.Field(key).String())
+	if err != nil {
+		return nil
+	}
+
+	return &number
+}
+
+//floatField returns the numeric value of a key, or nil when it isn't set to a number
+func floatField(value Value, key string) *float64 {
+	number, err := strconv.ParseFloat(value.Field(key).String(), 64)
+	if err != nil {
+		return nil
+	}
+
+	return &number
+}
+
+//Root returns the top level object of the template
+func (t *Template) Root() Value {
+	return newValue(t.document.Content[0])
+}
+
+//Parameter returns a parameter by name
+func (t *Template) Parameter(name string) (Parameter, bool) {
+	for _, parameter := range t.Parameters {
+		if parameter.Name == name {
+			return parameter, true
+		}
+	}
+
+	return Parameter{}, false
+}
+
+//Section returns the entries of a top level section, such as Resources or Rules, in order and including duplicates. Sections that aren't objects
+//have none
+func (t *Template) Section(name string) []Entry {
+	found := make([]Entry, 0)
+
+	for _, section := range entries(t.document.Content[0]) {
+		if section.Key == name {
+			found = append(found, entries(section.Value.node)...)
+		}
+	}
+
+	return found
+}
+
+//Resource returns a resource by logical ID
+func (t *Template) Resource(logicalID string) (Resource, bool) {
+	for _, resource := range t.Resources {
+		if resource.LogicalID == logicalID {
+			return resource, true
+		}
+	}
+
+	return Resource{}, false
+}
+
+//Output returns an output by name
+func (t *Template) Output(name string) (Output, bool) {
+	for _, output := range t.Outputs {
+		if output.Name == name {
+			return output, true
+		}
+	}
+
+	return Output{}, false
+}
+
+//IsTransformed determines if the template uses a transform, such as AWS::Serverless-2016-10-31, which adds resources during deployment
+func (t *Template) IsTransformed() bool {
+	return len(t.Transform) > 0
+}
@@ -0,0 +1,212 @@
+package template
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//Position is where something is in a template's source. Lines and columns start at 1, and are 0 when unknown
+type Position struct {
+	Line   int
+	Column int
+}
+
+//Value is a value in a template, such as a property or a condition. It's a literal, a list, an object or an intrinsic function, and wraps the
+//parsed node so changes to it are kept when the template is serialized
+type Value struct {
+	node *yaml.Node
+}
+
+//Entry is a key value pair of an object, in the order it's written
+type Entry struct {
+	Key   string
+	Value Value
+	Position
+}
+
+//Intrinsic is an intrinsic function call, written in full as Fn::Sub or in short form as !Sub. Name is always the full name, such as Ref or
+//Fn::GetAtt
+type Intrinsic struct {
+	Name     string
+	Argument Value
+}
+
+func newValue(node *yaml.Node) Value {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return Value{node: node}
+}
+
+//IsSet determines if a value is in the template. Values of keys that aren't set aren't
+func (v Value) IsSet() bool {
+	return v.node != nil
+}
+
+//Node returns the parsed node of a value, or nil when it isn't set
+func (v Value) Node() *yaml.Node {
+	return v.node
+}
+
+//Position returns where a value is in the template
+func (v Value) Position() Position {
+	if v.node == nil {
+		return Position{}
+	}
+
+	return Position{Line: v.node.Line, Column: v.node.Column}
+}
+
+//shortTag returns the short form intrinsic tag of a node, such as !Ref, or an empty string for a node without one
+func shortTag(node *yaml.Node) string {
+	if strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!") {
+		return node.Tag
+	}
+
+	return ""
+}
+
+//intrinsicName returns the full name of a short form intrinsic tag
+func intrinsicName(tag string) string {
+	switch tag {
+	case "!Ref", "!Condition":
+		return strings.TrimPrefix(tag, "!")
+	}
+
+	return "Fn::" + strings.TrimPrefix(tag, "!")
+}
+
+//isIntrinsicKey determines if the key of a single key object calls an intrinsic function
+func isIntrinsicKey(key string) bool {
+	return key == "Ref" || key == "Condition" || strings.HasPrefix(key, "Fn::")
+}
+
+//Intrinsic returns the intrinsic function a value calls, in short or full form. Its argument is untagged, so a short form !Ref Bucket has the
+//argument Bucket
+func (v Value) Intrinsic() (Intrinsic, bool) {
+	if v.node == nil {
+		return Intrinsic{}, false
+	}
+
+	if tag := shortTag(v.node); tag != "" {
+		argument := *v.node
+		argument.Tag = kindTags[v.node.Kind]
+
+		return Intrinsic{Name: intrinsicName(tag), Argument: Value{node: &argument}}, true
+	}
+
+	if v.node.Kind != yaml.MappingNode || len(v.node.Content) != 2 || !isIntrinsicKey(v.node.Content[0].Value) {
+		return Intrinsic{}, false
+	}
+
+	return Intrinsic{Name: v.node.Content[0].Value, Argument: newValue(v.node.Content[1])}, true
+}
+
+//kindTags are the tags a short form intrinsic's argument has without its intrinsic tag. Scalar arguments are always strings
+var kindTags map[yaml.Kind]string = map[yaml.Kind]string{
+	yaml.ScalarNode:   "!!str",
+	yaml.SequenceNode: "!!seq",
+	yaml.MappingNode:  "!!map",
+}
+
+//IsIntrinsic determines if a value calls an intrinsic function. Its value can't be known before deploying
+func (v Value) IsIntrinsic() bool {
+	_, ok := v.Intrinsic()
+	return ok
+}
+
+//IsNull determines if a value is set to null
+func (v Value) IsNull() bool {
+	return v.node != nil && v.node.Kind == yaml.ScalarNode && v.node.ShortTag() == "!!null"
+}
+
+//Literal returns the value of a string, number or boolean. Intrinsic functions, lists and objects aren't literals
+func (v Value) Literal() (string, bool) {
+	if v.node == nil || v.node.Kind != yaml.ScalarNode || shortTag(v.node) != "" || v.IsNull() {
+		return "", false
+	}
+
+	return v.node.Value, true
+}
+
+//String returns the literal value of a value, or an empty string for anything else
+func (v Value) String() string {
+	literal, _ := v.Literal()
+	return literal
+}
+
+//List returns the items of a list. Anything else has no items
+func (v Value) List() []Value {
+	if v.node == nil || v.node.Kind != yaml.SequenceNode || shortTag(v.node) != "" {
+		return nil
+	}
+
+	items := make([]Value, 0, len(v.node.Content))
+	for _, item := range v.node.Content {
+		items = append(items, newValue(item))
+	}
+
+	return items
+}
+
+//IsList determines if a value is a list. Intrinsic functions aren't lists
+func (v Value) IsList() bool {
+	return v.node != nil && v.node.Kind == yaml.SequenceNode && shortTag(v.node) == ""
+}
+
+//IsObject determines if a value is an object. Intrinsic functions aren't objects
+func (v Value) IsObject() bool {
+	return v.node != nil && v.node.Kind == yaml.MappingNode && !v.IsIntrinsic()
+}
+
+//Items returns the items of a list, or the value itself when it's a single value, as policy statements and actions can be. Values that aren't set
+//have no items
+func (v Value) Items() []Value {
+	if v.IsList() {
+		return v.List()
+	}
+
+	if v.node == nil {
+		return nil
+	}
+
+	return []Value{v}
+}
+
+//Strings returns the literal values of a single value or a list of values. Items that aren't literals are skipped
+func (v Value) Strings() []string {
+	if literal, ok := v.Literal(); ok {
+		return []string{literal}
+	}
+
+	values := make([]string, 0)
+	for _, item := range v.List() {
+		if literal, ok := item.Literal(); ok {
+			values = append(values, literal)
+		}
+	}
+
+	return values
+}
+
+//Entries returns the key value pairs of an object, in order and including duplicates. Intrinsic functions aren't objects
+func (v Value) Entries() []Entry {
+	if v.node == nil || v.node.Kind != yaml.MappingNode || v.IsIntrinsic() {
+		return nil
+	}
+
+	return entries(v.node)
+}
+
+//Field returns the value of a key of an object, which isn't set when the key isn't
+func (v Value) Field(key string) Value {
+	for _, entry := range v.Entries() {
+		if entry.Key == key {
+			return entry.Value
+		}
+	}
+
+	return Value{}
+}
@@ -1,129 +1,90 @@
 package ui
 
 import (
-	"context"
-	"fmt"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/blueseph/cirrus/cfn"
-	"github.com/blueseph/cirrus/colors"
 	"github.com/blueseph/cirrus/data"
-	"github.com/blueseph/cirrus/utils"
+	"github.com/gdamore/tcell"
 	"github.com/rivo/tview"
 )
 
-func declineButtonCallbackFn(app *tview.Application, operation cfn.StackOperation) func() {
-	return func() {
-		declined := "change set"
+func operationSubject(operation cfn.StackOperation) string {
+	if operation == cfn.StackOperationDelete {
+		return "delete"
+	}
 
-		if operation == cfn.StackOperationDelete {
-			declined = "delete"
-		}
+	return "change set"
+}
 
-		defer fmt.Println(colors.Status(fmt.Sprintf("User declined %s", declined)))
-		app.Stop()
+func declineButtonCallbackFn(r *InteractiveRenderer) func() {
+	return func() {
+		r.decision <- false
+		r.app.Stop()
 	}
 }
 
-func executeButtonCallbackFn(app *tview.Application, displayBox *tview.TextView, form *tview.Form, info data.StackInfo, operation cfn.StackOperation, displayRows map[string]data.DisplayRow, fillDisplayBox func(map[string]data.DisplayRow)) func() {
+func executeButtonCallbackFn(r *InteractiveRenderer, form *tview.Form) func() {
 	return func() {
-		resetForm(app, displayBox, form)
-
-		activatedDisplayRows := activateRowsAndRender(displayRows, fillDisplayBox)
-		executeOperation(operation, info)
+		if r.ready.RequiresTypedConfirmation() {
+			showTypedConfirmation(r, form)
+			return
+		}
 
-		go handleEventsLoop(app, form, info, activatedDisplayRows, fillDisplayBox)
+		execute(r, form)
 	}
 }
 
-func resetForm(app *tview.Application, displayBox *tview.TextView, form *tview.Form) {
-	form.ClearButtons().SetTitle(" Errors ")
+//showTypedConfirmation replaces the actions with a field for the typed confirmation, which executes the operation once it matches
+func showTypedConfirmation(r *InteractiveRenderer, form *tview.Form) {
+	// the tab workaround expects the execute and decline buttons, the form handles tab itself from here
+	r.view.SetInputCapture(nil)
+	r.app.SetInputCapture(nil)
 
-	app.SetFocus(displayBox)
-	app.SetInputCapture(nil)
-}
-
-func activateRowsAndRender(displayRows map[string]data.DisplayRow, fillDisplayBox func(map[string]data.DisplayRow)) map[string]data.DisplayRow {
-	activatedDisplayRows := data.ActivateDisplayRows(displayRows)
-	fillDisplayBox(activatedDisplayRows)
+	form.ClearButtons().SetTitle(" Confirm ")
+	form.AddInputField(typedQuestion(r.ready)+" ", "", 0, nil, nil)
 
-	return activatedDisplayRows
-}
+	input := form.GetFormItem(0).(*tview.InputField)
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
 
-func succeed(app *tview.Application) {
-	defer fmt.Println(colors.Success("Operation Succeeded"))
-	app.Stop()
-}
+		if !r.ready.ConfirmedBy(input.GetText()) {
+			input.SetLabel("[red]Doesn't match.[white] " + typedQuestion(r.ready) + " ").SetText("")
+			return
+		}
 
-func fail(app *tview.Application, errors []cloudformation.StackEvent) {
-	errorMsg := colors.Error("Operation failed. The following errors prevented the stack from deploying successfully: \n\n")
+		form.Clear(true)
+		execute(r, form)
+	})
 
-	for i, err := range errors {
-		errorMsg += colors.Magenta(*err.LogicalResourceId) + " - " + string(*err.ResourceStatusReason)
-		if i < len(errors)-1 {
-			errorMsg += "\n"
-		}
-	}
+	form.AddButton(declineButtonLabel, declineButtonCallbackFn(r))
 
-	defer fmt.Println(errorMsg)
-	app.Stop()
+	r.app.SetFocus(input)
 }
 
-func executeOperation(operation cfn.StackOperation, info data.StackInfo) {
-	var err error
+func execute(r *InteractiveRenderer, form *tview.Form) {
+	resetForm(r.app, r.displayBox, form)
+	r.app.SetInputCapture(displayBoxInputCaptureFn(r.app, r.displayBox, r.searchField, r.state, r.render))
 
-	if operation == cfn.StackOperationDelete {
-		err = cfn.DeleteStack(info)
-	} else {
-		err = cfn.ExecuteChangeSet(info)
-	}
+	r.state.Lock()
+	r.state.rows = data.ActivateDisplayRows(r.state.rows)
+	r.state.executing = true
+	r.state.start = time.Now()
+	r.state.Unlock()
 
-	if err != nil {
-		panic(err)
-	}
+	r.view.ResizeItem(r.environmentBox, 0, 0)
+	r.view.ResizeItem(r.securityBox, 0, 0)
+	r.render()
+	go r.watch()
+
+	r.decision <- true
 }
 
-func handleEventsLoop(app *tview.Application, form *tview.Form, info data.StackInfo, activatedDisplayRows map[string]data.DisplayRow, fillDisplayBox func(map[string]data.DisplayRow)) {
-	now := time.Now()
-
-	eventIds := make(map[string]bool)
-	errors := make([]cloudformation.StackEvent, 0)
-
-	for {
-		paginator := cfn.GetStackEvents(info)
-
-		for paginator.Next(context.TODO()) {
-			events := paginator.CurrentPage()
-
-			for _, event := range utils.ReverseEvents(events.StackEvents) {
-				if event.Timestamp.After(now) {
-					if *event.ResourceType == data.CloudformationStackResource {
-						if utils.ContainsStackStatus(data.RollbackStackStatus, event.ResourceStatus) {
-							addErrorBar(form)
-						}
-
-						if !utils.ContainsStackStatus(data.PendingStackStatus, event.ResourceStatus) {
-							if len(errors) > 0 {
-								fail(app, errors)
-							} else {
-								succeed(app)
-							}
-						}
-					} else if !eventIds[*event.EventId] {
-						activatedDisplayRows[*event.LogicalResourceId] = data.CreateDisplayRowFromEvent(event)
-
-						if utils.ContainsResourceStatus(data.NegativeEventStatus, event.ResourceStatus) {
-							errors = append(errors, event)
-						}
-
-						eventIds[*event.EventId] = true
-					}
-				}
-			}
-		}
+func resetForm(app *tview.Application, displayBox *tview.TextView, form *tview.Form) {
+	form.ClearButtons().SetTitle(" Errors ")
 
-		fillDisplayBox(activatedDisplayRows)
-		time.Sleep(500 * time.Millisecond)
-	}
+	app.SetFocus(displayBox)
+	app.SetInputCapture(nil)
 }
@@ -1,11 +1,18 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/colors"
 	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/security"
+	"github.com/blueseph/cirrus/utils"
 	"github.com/gdamore/tcell"
 	"github.com/rivo/tview"
 )
@@ -13,24 +20,240 @@ import (
 var (
 	executeButtonLabel string = "Execute"
 	declineButtonLabel string = "Decline"
+
+	refreshInterval = 500 * time.Millisecond
+
+	maxSecurityRows    int = 8
+	maxEnvironmentRows int = 8
 )
 
-//DisplayChanges shows the change set in a graphic interface and waits for response. Cancels the command if the user declines, or executes and tails the events log
-func DisplayChanges(info data.StackInfo, changeSet *cloudformation.DescribeChangeSetResponse, operation cfn.StackOperation) error {
-	displayRows := data.ChangeMap(changeSet.Changes, false)
+//displayState holds the display rows and resource timings rendered in the display box and how they are rendered. It is shared between the engine
+//and the input handlers
+type displayState struct {
+	sync.Mutex
+	rows      map[string]data.DisplayRow
+	timings   map[string]data.ResourceTiming
+	options   data.DisplayOptions
+	timeline  bool
+	executing bool
+	start     time.Time
+}
+
+//InteractiveRenderer renders the stack operation lifecycle in a graphic interface
+type InteractiveRenderer struct {
+	app     *tview.Application
+	state   *displayState
+	history data.DurationHistory
+	cancel  context.CancelFunc
+
+	titleBar       *tview.TextView
+	environmentBox *tview.TextView
+	securityBox    *tview.TextView
+	displayBox     *tview.TextView
+	searchField    *tview.InputField
+	actionBar      *tview.Form
+
+	view      *tview.Flex
+	info      data.StackInfo
+	operation cfn.StackOperation
+	ready     engine.ChangeSetReady
+
+	decision chan bool
+	done     chan struct{}
+	err      error
+}
+
+//NewInteractiveRenderer returns a renderer that shows a graphic interface. Cancel is called when the interface closes, so the engine stops if the
+//user quits before the operation finishes
+func NewInteractiveRenderer(cancel context.CancelFunc) *InteractiveRenderer {
+	return &InteractiveRenderer{
+		app:      tview.NewApplication(),
+		state:    newDisplayState(),
+		history:  data.GetDurationHistory(),
+		cancel:   cancel,
+		decision: make(chan bool, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+//Confirm shows the changes and waits for the user to execute or decline them. Destructive changes and guarded stacks need a typed confirmation
+//before executing
+func (r *InteractiveRenderer) Confirm(event engine.ChangeSetReady) (bool, error) {
+	r.info = event.Info
+	r.operation = event.Operation
+	r.ready = event
+
+	r.state.Lock()
+	for key, row := range event.DisplayRows {
+		r.state.rows[key] = row
+	}
+	r.state.Unlock()
+
+	go r.run(r.createScreen())
+
+	select {
+	case confirmed := <-r.decision:
+		if !confirmed {
+			<-r.done
+			fmt.Println(colors.Status(fmt.Sprintf("User declined %s", operationSubject(r.operation))))
+		}
+
+		return confirmed, r.exitErr()
+	case <-r.done:
+		return false, r.err
+	}
+}
+
+//Render updates the interface with a lifecycle event. Once the stack reaches a terminal status, it waits for the interface to close and prints a
+//summary
+func (r *InteractiveRenderer) Render(event engine.Event) error {
+	switch event := event.(type) {
+	case engine.ResourceUpdated:
+		r.state.Lock()
+		r.state.rows[event.Row.LogicalResourceID] = event.Row
+		r.state.timings[event.Row.LogicalResourceID] = event.Timing
+		r.state.Unlock()
+
+		r.refresh()
+	case engine.StackUpdated:
+		if utils.ContainsStackStatus(data.RollbackStackStatus, event.Event.ResourceStatus) {
+			r.queue(func() {
+				addErrorBar(r.actionBar)
+			})
+		}
+	case engine.StackCompleted:
+		r.refresh()
+		r.app.Stop()
+		<-r.done
+
+		fmt.Println(colors.Success("Operation Succeeded"))
+		fmt.Print(formatTimingSummary(event.Timings))
+		fmt.Print(formatRetainedSummary(event.Retained))
+	case engine.StackFailed:
+		r.queue(func() {
+			showFailureScreen(r.app, r.info, r.operation, event.Failures, event.Events)
+		})
+		<-r.done
 
-	err := showScreen(displayRows, operation, info)
+		fmt.Println(formatFailureSummary(event.Failures))
+		fmt.Print(formatTimingSummary(event.Timings))
+	}
 
-	return err
+	return r.exitErr()
 }
 
-//DisplayDeletes shows the stack resoures and tails the events log.
-func DisplayDeletes(info data.StackInfo, resources []cloudformation.StackResourceSummary) error {
-	displayRows := data.ResourceMap(resources)
+func (r *InteractiveRenderer) run(view tview.Primitive) {
+	defer r.cancel()
+	defer close(r.done)
+
+	r.err = r.app.SetRoot(view, true).SetFocus(r.displayBox).Run()
+}
+
+//exitErr returns the error the interface closed with. The error is only written before the interface closes, so it's only read after
+func (r *InteractiveRenderer) exitErr() error {
+	select {
+	case <-r.done:
+		return r.err
+	default:
+		return nil
+	}
+}
 
-	err := showScreen(displayRows, cfn.StackOperationDelete, info)
+//queue runs an update of the widgets on the interface's event loop and redraws it, waiting until it's done. Updates after the interface closes
+//are dropped, since nothing runs them
+func (r *InteractiveRenderer) queue(update func()) {
+	queued := make(chan struct{})
+
+	go func() {
+		r.app.QueueUpdateDraw(update)
+		close(queued)
+	}()
+
+	select {
+	case <-queued:
+	case <-r.done:
+	}
+}
+
+//watch refreshes the interface so elapsed time and in progress timings keep moving between events
+func (r *InteractiveRenderer) watch() {
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-time.After(refreshInterval):
+			r.refresh()
+		}
+	}
+}
 
-	return err
+//refresh re-renders the interface from outside its event loop, such as from the engine
+func (r *InteractiveRenderer) refresh() {
+	r.queue(r.render)
+}
+
+//render re-renders the title bar and display box from the display state. It changes widgets, so it's only called on the event loop, or before
+//the interface runs
+func (r *InteractiveRenderer) render() {
+	now := time.Now()
+
+	r.state.Lock()
+
+	title := getDisplayBoxTitle(r.state)
+	header := getTitleBar(r.info, r.operation)
+	text := ParseDisplayRows(r.state.rows, r.state.options)
+
+	if !r.state.executing && r.ready.Guarded != "" {
+		header += getGuardedBar(r.ready.Guarded)
+	}
+
+	if !r.state.executing && len(r.ready.Destructive) > 0 {
+		header += getDestructiveBar(r.ready.Destructive)
+	}
+
+	if r.state.executing {
+		remaining, estimated := data.EstimateRemaining(r.history, r.state.rows, r.state.timings, now)
+		header += getProgressBar(data.GetProgress(r.state.rows), now.Sub(r.state.start), remaining, estimated)
+	}
+
+	if r.state.timeline {
+		_, _, width, _ := r.displayBox.GetInnerRect()
+		text = ParseTimeline(r.state.timings, now, width)
+	}
+
+	r.state.Unlock()
+
+	r.titleBar.SetText(header)
+	r.displayBox.SetTitle(title)
+	r.displayBox.SetText(text)
+}
+
+func (r *InteractiveRenderer) createScreen() tview.Primitive {
+	r.displayBox = createDisplayRowBox(r.app)
+	r.searchField = createSearchField(r.app, r.displayBox, r.state, r.render)
+	r.titleBar = createTitleBar(r.info, r.operation)
+	r.environmentBox = createEnvironmentBox(r.ready.Environment)
+	r.securityBox = createSecurityBox(r.ready.Security)
+	r.actionBar = createActionBar(r)
+
+	r.render()
+
+	r.view = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(r.titleBar, 6, 0, false).
+		AddItem(r.environmentBox, environmentBoxHeight(r.ready.Environment), 0, false).
+		AddItem(r.securityBox, securityBoxHeight(r.ready.Security), 0, false).
+		AddItem(r.displayBox, 0, 3, false).
+		AddItem(r.searchField, 1, 0, false).
+		AddItem(r.actionBar, 5, 0, false)
+
+	viewSetInputCapture := viewInputCaptureFn(r.app, r.actionBar, r.displayBox)
+	r.view.SetInputCapture(viewSetInputCapture)
+
+	displayBoxInputCapture := displayBoxInputCaptureFn(r.app, r.displayBox, r.searchField, r.state, r.render)
+	appSetInputCapture := appSetInputCaptureFn(r.view, displayBoxInputCapture)
+	r.app.SetInputCapture(appSetInputCapture)
+
+	return r.view
 }
 
 func createTitleBar(info data.StackInfo, operation cfn.StackOperation) *tview.TextView {
@@ -43,6 +266,57 @@ func createTitleBar(info data.StackInfo, operation cfn.StackOperation) *tview.Te
 	return textView
 }
 
+//createSecurityBox creates the security summary shown above the changes while they're reviewed
+func createSecurityBox(findings []security.Finding) *tview.TextView {
+	securityBox := tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	securityBox.SetBorder(true).SetTitle(" Security ")
+
+	if len(findings) > 0 {
+		securityBox.SetText(formatSecurityFindings(findings))
+	}
+
+	return securityBox
+}
+
+//securityBoxHeight fits the security summary, up to a limit so the changes stay visible. Without findings, the box is hidden
+func securityBoxHeight(findings []security.Finding) int {
+	if len(findings) == 0 {
+		return 0
+	}
+
+	if len(findings) > maxSecurityRows {
+		return maxSecurityRows + 3
+	}
+
+	return len(findings) + 3
+}
+
+//createEnvironmentBox creates the summary of the environment's effective parameters and tags shown above the changes while they're reviewed
+func createEnvironmentBox(environment *data.Environment) *tview.TextView {
+	environmentBox := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(false)
+	environmentBox.SetBorder(true).SetTitle(" Environment ")
+
+	if environment != nil {
+		environmentBox.SetText(formatEnvironment(environment))
+	}
+
+	return environmentBox
+}
+
+//environmentBoxHeight fits the environment summary, up to a limit so the changes stay visible. Without an environment, the box is hidden
+func environmentBoxHeight(environment *data.Environment) int {
+	if environment == nil {
+		return 0
+	}
+
+	rows := strings.Count(formatEnvironment(environment), "\n")
+	if rows > maxEnvironmentRows {
+		return maxEnvironmentRows + 2
+	}
+
+	return rows + 2
+}
+
 func createDisplayRowBox(app *tview.Application) *tview.TextView {
 	textView := tview.NewTextView().SetRegions(true).SetScrollable(true).SetDynamicColors(true).SetWrap(false).
 		SetChangedFunc(func() {
@@ -54,18 +328,45 @@ func createDisplayRowBox(app *tview.Application) *tview.TextView {
 	return textView
 }
 
-func fillDisplayBoxFn(displayBox *tview.TextView) func(map[string]data.DisplayRow) {
-	return func(displayRows map[string]data.DisplayRow) {
-		displayBox.SetText(ParseDisplayRows(displayRows))
+func newDisplayState() *displayState {
+	return &displayState{
+		rows:    make(map[string]data.DisplayRow),
+		timings: make(map[string]data.ResourceTiming),
+		options: data.DisplayOptions{Sort: data.SortByLogicalID, Collapsed: make(map[string]bool)},
 	}
 }
 
-func createActionBar(app *tview.Application, displayBox *tview.TextView, info data.StackInfo, operation cfn.StackOperation, displayRows map[string]data.DisplayRow, fillDisplayBox func(map[string]data.DisplayRow)) *tview.Form {
+func createSearchField(app *tview.Application, displayBox *tview.TextView, state *displayState, refresh func()) *tview.InputField {
+	searchField := tview.NewInputField().
+		SetLabel(" / ").
+		SetPlaceholder("search by logical ID or type").
+		SetFieldWidth(0)
+
+	searchField.
+		SetChangedFunc(func(text string) {
+			state.Lock()
+			state.options.Search = text
+			state.Unlock()
+
+			refresh()
+		}).
+		SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEscape {
+				searchField.SetText("")
+			}
+
+			app.SetFocus(displayBox)
+		})
+
+	return searchField
+}
+
+func createActionBar(r *InteractiveRenderer) *tview.Form {
 	form := tview.NewForm()
 
 	form.
-		AddButton(executeButtonLabel, executeButtonCallbackFn(app, displayBox, form, info, operation, displayRows, fillDisplayBox)).
-		AddButton(declineButtonLabel, declineButtonCallbackFn(app, operation))
+		AddButton(executeButtonLabel, executeButtonCallbackFn(r, form)).
+		AddButton(declineButtonLabel, declineButtonCallbackFn(r))
 
 	form.SetButtonsAlign(tview.AlignCenter).SetBorder(true).SetTitle(" Actions ")
 
@@ -80,38 +381,13 @@ func addErrorBar(form *tview.Form) {
 	form.AddFormItem(errorBar)
 }
 
-func showScreen(displayRows map[string]data.DisplayRow, operation cfn.StackOperation, info data.StackInfo) error {
-	app := tview.NewApplication()
-
-	displayBox := createDisplayRowBox(app)
-	fillDisplayBox := fillDisplayBoxFn(displayBox)
-
-	titleBar := createTitleBar(info, operation)
-	actionBar := createActionBar(app, displayBox, info, operation, displayRows, fillDisplayBox)
-
-	fillDisplayBox(displayRows)
-
-	view := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(titleBar, 5, 0, false).
-		AddItem(displayBox, 0, 3, false).
-		AddItem(actionBar, 5, 0, false)
-
-	viewSetInputCapture := viewInputCaptureFn(app, actionBar, displayBox)
-	view.SetInputCapture(viewSetInputCapture)
-
-	appSetInputCapture := appSetInputCaptureFn(view)
-	app.SetInputCapture(appSetInputCapture)
-
-	if err := app.SetRoot(view, true).SetFocus(displayBox).Run(); err != nil {
-		panic(err)
-	}
-
-	return nil
-}
-
 //hacky workaround
-func appSetInputCaptureFn(view *tview.Flex) func(*tcell.EventKey) *tcell.EventKey {
+func appSetInputCaptureFn(view *tview.Flex, displayBoxInputCapture func(*tcell.EventKey) *tcell.EventKey) func(*tcell.EventKey) *tcell.EventKey {
 	return func(e *tcell.EventKey) *tcell.EventKey {
+		if e = displayBoxInputCapture(e); e == nil {
+			return nil
+		}
+
 		if view.HasFocus() {
 			view.GetInputCapture()(e)
 		}
@@ -155,3 +431,101 @@ func viewInputCaptureFn(app *tview.Application, actionBar *tview.Form, displayBo
 		return e
 	}
 }
+
+//displayBoxInputCaptureFn handles the display option shortcuts while the display box has focus
+func displayBoxInputCaptureFn(app *tview.Application, displayBox *tview.TextView, searchField *tview.InputField, state *displayState, refresh func()) func(*tcell.EventKey) *tcell.EventKey {
+	return func(e *tcell.EventKey) *tcell.EventKey {
+		if !displayBox.HasFocus() {
+			return e
+		}
+
+		if e.Key() == tcell.KeyEnter {
+			return toggleNestedStack(displayBox, state, refresh, e)
+		}
+
+		if e.Key() != tcell.KeyRune {
+			return e
+		}
+
+		state.Lock()
+
+		switch e.Rune() {
+		case 'n':
+			state.Unlock()
+			highlightNextNestedStack(displayBox, state)
+			return nil
+		case '/':
+			state.Unlock()
+			app.SetFocus(searchField)
+			return nil
+		case 's':
+			state.options.Sort = data.NextSortOrder(state.options.Sort)
+		case 'c':
+			state.options.HideComplete = !state.options.HideComplete
+		case 'u':
+			state.options.HideUnchanged = !state.options.HideUnchanged
+		case 't':
+			if !state.executing {
+				state.Unlock()
+				return e
+			}
+
+			state.timeline = !state.timeline
+		default:
+			state.Unlock()
+			return e
+		}
+
+		state.Unlock()
+		refresh()
+
+		return nil
+	}
+}
+
+//highlightNextNestedStack moves the highlight to the next visible nested stack row, wrapping around to the first
+func highlightNextNestedStack(displayBox *tview.TextView, state *displayState) {
+	state.Lock()
+	rows := data.FlattenDisplayRows(state.rows, state.options)
+	state.Unlock()
+
+	regions := make([]string, 0)
+	for _, row := range rows {
+		if isNestedStackRow(row) {
+			regions = append(regions, nestedStackRegion(row.LogicalResourceID))
+		}
+	}
+
+	if len(regions) == 0 {
+		return
+	}
+
+	next := regions[0]
+	if highlights := displayBox.GetHighlights(); len(highlights) > 0 {
+		for i, region := range regions {
+			if region == highlights[0] && i < len(regions)-1 {
+				next = regions[i+1]
+			}
+		}
+	}
+
+	displayBox.Highlight(next).ScrollToHighlight()
+}
+
+//toggleNestedStack collapses or expands the highlighted nested stack row
+func toggleNestedStack(displayBox *tview.TextView, state *displayState, refresh func(), e *tcell.EventKey) *tcell.EventKey {
+	highlights := displayBox.GetHighlights()
+	if len(highlights) == 0 {
+		return e
+	}
+
+	key := nestedStackKey(highlights[0])
+
+	state.Lock()
+	state.options.Collapsed[key] = !state.options.Collapsed[key]
+	state.Unlock()
+
+	refresh()
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/utils"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+var (
+	saveLogButtonLabel string = "Save Log"
+	eventsButtonLabel  string = "Events"
+	quitButtonLabel    string = "Quit"
+	backButtonLabel    string = "Back"
+
+	failurePage string = "failure"
+	eventsPage  string = "events"
+)
+
+func createFailureBox(failures []cloudformation.StackEvent, timeline []cloudformation.StackEvent) *tview.TextView {
+	textView := tview.NewTextView().SetScrollable(true).SetDynamicColors(true).SetWrap(false)
+
+	textView.SetText(formatFailures(failures) + "\n" + formatTimeline(rollbackTimeline(timeline)))
+	textView.SetBorder(true).SetTitle(" Failure Log ")
+
+	return textView
+}
+
+func createEventsBox(timeline []cloudformation.StackEvent) *tview.TextView {
+	textView := tview.NewTextView().SetScrollable(true).SetDynamicColors(true).SetWrap(false)
+
+	textView.SetText(formatEvents(timeline))
+	textView.SetBorder(true).SetTitle(" Events ")
+
+	return textView
+}
+
+func saveLogButtonCallbackFn(form *tview.Form, info data.StackInfo, failures []cloudformation.StackEvent, timeline []cloudformation.StackEvent) func() {
+	return func() {
+		location := fmt.Sprintf("%s-failure-%d.log", info.StackName, time.Now().Unix())
+
+		err := ioutil.WriteFile(location, []byte(formatFailureLog(info, failures, timeline)), 0644)
+		if err != nil {
+			form.SetTitle(" Unable to save log: " + tview.Escape(err.Error()) + " ")
+			return
+		}
+
+		form.SetTitle(" Log saved to " + location + " ")
+	}
+}
+
+//rollbackTimeline returns the events from the first failure onwards, ordered from oldest to newest
+func rollbackTimeline(timeline []cloudformation.StackEvent) []cloudformation.StackEvent {
+	sorted := utils.SortEvents(timeline)
+
+	for i, event := range sorted {
+		if utils.ContainsResourceStatus(data.NegativeEventStatus, event.ResourceStatus) || utils.ContainsStackStatus(data.RollbackStackStatus, event.ResourceStatus) {
+			return sorted[i:]
+		}
+	}
+
+	return sorted
+}
+
+func switchPageFn(app *tview.Application, pages *tview.Pages, page string, focus tview.Primitive) func() {
+	return func() {
+		pages.SwitchToPage(page)
+		app.SetFocus(focus)
+	}
+}
+
+//showFailureScreen replaces the running screen with the failure log and an events browser. The application keeps running until the user quits, after which the renderer prints
+//the failure summary
+func showFailureScreen(app *tview.Application, info data.StackInfo, operation cfn.StackOperation, failures []cloudformation.StackEvent, timeline []cloudformation.StackEvent) {
+	pages := tview.NewPages()
+
+	failureBox := createFailureBox(failures, timeline)
+	eventsBox := createEventsBox(timeline)
+
+	failureForm := tview.NewForm()
+	eventsForm := tview.NewForm()
+
+	showFailures := switchPageFn(app, pages, failurePage, failureBox)
+	showEvents := switchPageFn(app, pages, eventsPage, eventsBox)
+	saveLog := saveLogButtonCallbackFn(failureForm, info, failures, timeline)
+	quit := app.Stop
+
+	failureForm.
+		AddButton(saveLogButtonLabel, saveLog).
+		AddButton(eventsButtonLabel, showEvents).
+		AddButton(quitButtonLabel, quit)
+
+	failureForm.SetCancelFunc(showFailures).SetButtonsAlign(tview.AlignCenter).SetBorder(true).SetTitle(" Actions ")
+
+	eventsForm.
+		AddButton(backButtonLabel, showFailures).
+		AddButton(quitButtonLabel, quit)
+
+	eventsForm.SetCancelFunc(showEvents).SetButtonsAlign(tview.AlignCenter).SetBorder(true).SetTitle(" Actions ")
+
+	failureView := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(createTitleBar(info, operation), 5, 0, false).
+		AddItem(failureBox, 0, 3, true).
+		AddItem(failureForm, 5, 0, false)
+
+	eventsView := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(createTitleBar(info, operation), 5, 0, false).
+		AddItem(eventsBox, 0, 3, true).
+		AddItem(eventsForm, 5, 0, false)
+
+	pages.
+		AddPage(failurePage, failureView, true, true).
+		AddPage(eventsPage, eventsView, true, false)
+
+	app.SetInputCapture(failureInputCaptureFn(app, pages, failureBox, failureForm, eventsBox, eventsForm, saveLog, showFailures, showEvents, quit))
+	app.SetRoot(pages, true).SetFocus(failureBox)
+}
+
+func failureInputCaptureFn(app *tview.Application, pages *tview.Pages, failureBox *tview.TextView, failureForm *tview.Form, eventsBox *tview.TextView, eventsForm *tview.Form, saveLog func(), showFailures func(), showEvents func(), quit func()) func(*tcell.EventKey) *tcell.EventKey {
+	return func(e *tcell.EventKey) *tcell.EventKey {
+		page, _ := pages.GetFrontPage()
+
+		box, form := failureBox, failureForm
+		if page == eventsPage {
+			box, form = eventsBox, eventsForm
+		}
+
+		if !box.HasFocus() {
+			return e
+		}
+
+		switch {
+		case e.Key() == tcell.KeyTab || e.Key() == tcell.KeyBacktab:
+			app.SetFocus(form)
+			return nil
+		case e.Rune() == 's' && page == failurePage:
+			saveLog()
+			return nil
+		case e.Rune() == 'e' && page == failurePage:
+			showEvents()
+			return nil
+		case e.Rune() == 'b' && page == eventsPage:
+			showFailures()
+			return nil
+		case e.Rune() == 'q':
+			quit()
+			return nil
+		}
+
+		return e
+	}
+}
@@ -1,13 +1,26 @@
 package ui
 
 import (
+	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/colors"
 	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/lint"
+	"github.com/blueseph/cirrus/policy"
+	"github.com/blueseph/cirrus/security"
 	"github.com/blueseph/cirrus/utils"
+	"github.com/rivo/tview"
+)
+
+const (
+	timestampFormat     string = "15:04:05"
+	longestRunningCount int    = 5
 )
 
 func stackOperationColorize(operation cfn.StackOperation) string {
@@ -78,15 +91,93 @@ func getTitleBar(info data.StackInfo, operation cfn.StackOperation) string {
 	return title
 }
 
-func parseDisplayRow(row data.DisplayRow) string {
+func getGuardedBar(guarded string) string {
+	return "[red::b]Guarded:     [white::-]matches guard rule [white::b]" + guarded + "[white::-]\n"
+}
+
+func getDestructiveBar(destructive []data.DisplayRow) string {
+	ids := make([]string, 0, len(destructive))
+	for _, row := range destructive {
+		ids = append(ids, row.LogicalResourceID)
+	}
+
+	return fmt.Sprintf("[red::b]Destructive: [white::-]%d stateful resources removed or replaced: [white::b]%s[white::-]\n", len(destructive), strings.Join(ids, ", "))
+}
+
+func getProgressBar(progress data.Progress, elapsed time.Duration, remaining time.Duration, estimated bool) string {
+	eta := "unknown"
+	if estimated {
+		eta = "~" + formatDuration(remaining)
+	}
+
+	var bar string
+	bar += fmt.Sprintf("[white]Progress:  [green::b]%d[white::-] of [white::b]%d[white::-] complete, ", progress.Complete, progress.Total)
+	bar += fmt.Sprintf("[yellow::b]%d[white::-] in progress, [red::b]%d[white::-] failed", progress.InProgress, progress.Failed)
+	bar += "    Elapsed: [white::b]" + formatDuration(elapsed) + "[white::-]    ETA: [white::b]" + eta + "[white::-]\n"
+
+	return bar
+}
+
+//nestedStackRegion returns the tview region ID of a nested stack row. Region IDs can't contain the nested stack separator
+func nestedStackRegion(key string) string {
+	return strings.ReplaceAll(key, data.NestedStackSeparator, ".")
+}
+
+func nestedStackKey(region string) string {
+	return strings.ReplaceAll(region, ".", data.NestedStackSeparator)
+}
+
+func isNestedStackRow(row data.TreeRow) bool {
+	return row.ResourceType == data.CloudformationStackResource || row.Descendants > 0
+}
+
+func parseTreePrefix(row data.TreeRow) string {
+	prefix := strings.Repeat("    ", row.Depth)
+
+	if !isNestedStackRow(row) {
+		return prefix
+	}
+
+	if row.Collapsed {
+		return prefix + "[white]▸ "
+	}
+
+	return prefix + "[white]▾ "
+}
+
+func parseTreeSuffix(row data.TreeRow) string {
+	if !row.Collapsed || row.Descendants == 0 {
+		return ""
+	}
+
+	suffix := fmt.Sprintf(" [grey](%d resources)[white]", row.Descendants)
+
+	if row.Failed > 0 {
+		suffix += fmt.Sprintf(" [red::b]%d failed[white::-]", row.Failed)
+	}
+
+	return suffix
+}
+
+func parseDisplayRow(row data.TreeRow) string {
+	var formatted string
+
 	if row.Source == data.DisplayRowSourceEvent {
-		return parseEventRow(row)
+		formatted = parseEventRow(row)
+	} else {
+		formatted = parseRow(row)
 	}
 
-	return parseRow(row)
+	formatted = parseTreePrefix(row) + formatted + parseTreeSuffix(row)
+
+	if isNestedStackRow(row) {
+		formatted = `["` + nestedStackRegion(row.LogicalResourceID) + `"]` + formatted + `[""]`
+	}
+
+	return formatted + "\n"
 }
 
-func parseRow(row data.DisplayRow) string {
+func parseRow(row data.TreeRow) string {
 	var formatted string
 	replacement := row.Replacement
 
@@ -96,12 +187,16 @@ func parseRow(row data.DisplayRow) string {
 		formatted += "[" + colorizeAction(row.Action, true) + "] "
 	}
 
-	formatted += "[#00b8ea]" + row.LogicalResourceID + " [white]"
+	formatted += "[#00b8ea]" + row.Name() + " [white]"
 	if !row.Active {
 		formatted += colorizeAction(row.Action, false) + " "
 	}
 	formatted += resourceTypeFormat(row.ResourceType)
 
+	if data.IsRetained(row.DisplayRow) {
+		formatted += " [yellow]" + string(row.DeletionPolicy) + "[white]"
+	}
+
 	if !row.Active {
 		if replacement == cloudformation.ReplacementTrue {
 			formatted += " [red]Replace[white]"
@@ -112,33 +207,588 @@ func parseRow(row data.DisplayRow) string {
 		}
 	}
 
-	return formatted + "\n"
+	return formatted
 }
 
-func parseEventRow(row data.DisplayRow) string {
+func parseEventRow(row data.TreeRow) string {
 	var formatted string
 
 	formatted += "[" + colorizeResourceStatus(row.Status) + "]"
-	formatted += "[#00b8ea]" + row.LogicalResourceID + " [white]"
+	formatted += "[#00b8ea]" + row.Name() + " [white]"
 	formatted += resourceTypeFormat(row.ResourceType)
 
-	return formatted + "\n"
+	return formatted
 }
 
-//ParseDisplayRows parses and sorts the map of display rows and returns a tview.TextBox consumable string
-func ParseDisplayRows(displayRows map[string]data.DisplayRow) string {
-	keys := make([]string, 0)
+//ParseDisplayRows filters and sorts the map of display rows with the display options, nesting the resources of nested stacks beneath them, and
+//returns a tview.TextBox consumable string
+func ParseDisplayRows(displayRows map[string]data.DisplayRow, options data.DisplayOptions) string {
 	var allChanges string
 
-	for key := range displayRows {
-		keys = append(keys, key)
+	for _, row := range data.FlattenDisplayRows(displayRows, options) {
+		msg := parseDisplayRow(row)
+		allChanges += msg
+	}
+	return allChanges
+}
+
+func getDisplayBoxTitle(state *displayState) string {
+	title := " Changes "
+	if state.timeline {
+		title = " Timeline "
 	}
 
-	sort.Strings(keys)
+	settings := make([]string, 0)
 
-	for _, key := range keys {
-		msg := parseDisplayRow(displayRows[key])
-		allChanges += msg
+	if state.options.Sort != data.SortByLogicalID {
+		settings = append(settings, "sort: "+string(state.options.Sort))
 	}
-	return allChanges
+
+	if state.options.HideComplete {
+		settings = append(settings, "hiding complete")
+	}
+
+	if state.options.HideUnchanged {
+		settings = append(settings, "hiding unchanged")
+	}
+
+	if state.options.Search != "" {
+		settings = append(settings, "search: "+tview.Escape(state.options.Search))
+	}
+
+	if len(settings) == 0 {
+		settings = append(settings, "/: search", "s: sort", "c: hide complete", "u: hide unchanged")
+
+		for _, row := range state.rows {
+			if row.ResourceType == data.CloudformationStackResource {
+				settings = append(settings, "n/enter: nested stacks")
+				break
+			}
+		}
+
+		if state.executing {
+			settings = append(settings, "t: timeline")
+		}
+	}
+
+	return title + "(" + strings.Join(settings, ", ") + ") "
+}
+
+func eventReason(event cloudformation.StackEvent) string {
+	if event.ResourceStatusReason == nil {
+		return ""
+	}
+
+	return *event.ResourceStatusReason
+}
+
+func parseTimelineEvent(event cloudformation.StackEvent) string {
+	var formatted string
+
+	formatted += "[grey]" + event.Timestamp.Local().Format(timestampFormat) + " [white]"
+	formatted += "[" + colorizeResourceStatus(event.ResourceStatus) + "] "
+	formatted += "[#00b8ea]" + *event.LogicalResourceId + " [white]"
+	formatted += tview.Escape(eventReason(event))
+
+	return formatted + "\n"
+}
+
+func formatFailures(failures []cloudformation.StackEvent) string {
+	formatted := "[red::b]Failed resources[white::-]\n\n"
+
+	for _, failure := range failures {
+		formatted += "[#00b8ea]" + *failure.LogicalResourceId + " [white]" + resourceTypeFormat(*failure.ResourceType) + "\n"
+		formatted += "    " + tview.Escape(eventReason(failure)) + "\n"
+	}
+
+	return formatted
+}
+
+func formatFailureSummary(failures []cloudformation.StackEvent) string {
+	summary := colors.Error("Operation failed. The following errors prevented the stack from deploying successfully: \n\n")
+
+	for i, failure := range failures {
+		summary += colors.Magenta(*failure.LogicalResourceId) + " - " + eventReason(failure)
+		if i < len(failures)-1 {
+			summary += "\n"
+		}
+	}
+
+	return summary
+}
+
+func formatTimeline(events []cloudformation.StackEvent) string {
+	formatted := "[white::b]Rollback timeline[white::-]\n\n"
+
+	for _, event := range events {
+		formatted += parseTimelineEvent(event)
+	}
+
+	return formatted
+}
+
+func formatEvents(events []cloudformation.StackEvent) string {
+	var formatted string
+
+	for _, event := range utils.SortEvents(events) {
+		formatted += parseTimelineEvent(event)
+		formatted += "         " + resourceTypeFormat(*event.ResourceType)
+
+		if event.PhysicalResourceId != nil {
+			formatted += " [grey]" + tview.Escape(*event.PhysicalResourceId) + "[white]"
+		}
+
+		formatted += "\n"
+	}
+
+	return formatted
+}
+
+//formatFailureLog returns a plain text failure log suitable for writing to a file
+func formatFailureLog(info data.StackInfo, failures []cloudformation.StackEvent, timeline []cloudformation.StackEvent) string {
+	var formatted string
+
+	formatted += fmt.Sprintf("Stack:     %s\n", info.StackName)
+	formatted += fmt.Sprintf("Id:        %s\n", info.StackID)
+	if info.ChangeSetName != "" {
+		formatted += fmt.Sprintf("Changeset: %s\n", info.ChangeSetName)
+	}
+
+	formatted += "\nFailed resources\n----------------\n"
+	for _, failure := range failures {
+		formatted += fmt.Sprintf("%s (%s) - %s\n", *failure.LogicalResourceId, *failure.ResourceType, eventReason(failure))
+	}
+
+	formatted += "\nEvents\n------\n"
+	for _, event := range utils.SortEvents(timeline) {
+		formatted += fmt.Sprintf("%s %s %s %s\n", event.Timestamp.Local().Format(time.RFC3339), event.ResourceStatus, *event.LogicalResourceId, eventReason(event))
+	}
+
+	return formatted
+}
+
+func colorizeTimingBar(timing data.ResourceTiming, length int) string {
+	color := "[green]"
+	end := "[white]"
+
+	if !timing.Complete() {
+		color = "[yellow]"
+	}
+
+	if utils.ContainsResourceStatus(data.NegativeEventStatus, timing.Status) {
+		color = "[red]"
+	}
+
+	return color + strings.Repeat("█", length) + end
+}
+
+func formatDuration(duration time.Duration) string {
+	return duration.Round(time.Second).String()
+}
+
+//ParseTimeline renders the resource timings as horizontal bars scaled to the given width and returns a tview.TextBox consumable string
+func ParseTimeline(timings map[string]data.ResourceTiming, now time.Time, width int) string {
+	sorted := data.SortTimings(timings)
+	if len(sorted) == 0 {
+		return "[grey]Waiting for resource events...[white]\n"
+	}
+
+	labelWidth := 0
+	start := sorted[0].Start
+	end := start
+
+	for _, timing := range sorted {
+		if len(timing.LogicalResourceID) > labelWidth {
+			labelWidth = len(timing.LogicalResourceID)
+		}
+
+		finish := now
+		if timing.Complete() {
+			finish = timing.End
+		}
+
+		if finish.After(end) {
+			end = finish
+		}
+	}
+
+	barWidth := width - labelWidth - 12
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	span := end.Sub(start)
+	if span <= 0 {
+		span = time.Second
+	}
+
+	var formatted string
+
+	for _, timing := range sorted {
+		offset := int(int64(barWidth) * int64(timing.Start.Sub(start)) / int64(span))
+		length := int(int64(barWidth) * int64(timing.Duration(now)) / int64(span))
+
+		if length < 1 {
+			length = 1
+		}
+
+		if offset+length > barWidth {
+			offset = barWidth - length
+		}
+
+		formatted += fmt.Sprintf("[#00b8ea]%-*s [white]", labelWidth, timing.LogicalResourceID)
+		formatted += strings.Repeat(" ", offset) + colorizeTimingBar(timing, length) + strings.Repeat(" ", barWidth-offset-length)
+		formatted += " [grey]" + formatDuration(timing.Duration(now)) + "[white]\n"
+	}
+
+	return formatted
+}
+
+//formatTimingSummary returns a plain text summary of the critical path and the longest running resources
+func formatTimingSummary(timings map[string]data.ResourceTiming) string {
+	now := time.Now()
+	path := data.CriticalPath(timings)
+
+	if len(path) == 0 {
+		return ""
+	}
+
+	formatted := "\nDeployment Timeline\n-------------------\n"
+	formatted += fmt.Sprintf("Total: %s\n", formatDuration(path[len(path)-1].End.Sub(data.SortTimings(timings)[0].Start)))
+
+	formatted += "Critical path: "
+	for i, timing := range path {
+		formatted += fmt.Sprintf("%s (%s)", colors.Teal(timing.LogicalResourceID), formatDuration(timing.Duration(now)))
+		if i < len(path)-1 {
+			formatted += " → "
+		}
+	}
+
+	formatted += "\nLongest running:\n"
+	for _, timing := range data.LongestRunning(timings, longestRunningCount, now) {
+		formatted += fmt.Sprintf("  %s %s %s\n", colors.Teal(timing.LogicalResourceID), timing.ResourceType, formatDuration(timing.Duration(now)))
+	}
+
+	return formatted
+}
+
+func formatRetainedSummary(retained []data.RetainedResource) string {
+	if len(retained) == 0 {
+		return ""
+	}
+
+	formatted := colors.Status("The following resources were kept and may still be billed:") + "\n"
+	for _, resource := range retained {
+		formatted += fmt.Sprintf("  %s %s %s (%s)\n", colors.Teal(resource.LogicalResourceID), resource.ResourceType, resource.PhysicalResourceID, retainedLabel(resource.DeletionPolicy))
+	}
+
+	return formatted
+}
+
+func retainedLabel(policy data.DeletionPolicy) string {
+	if policy == data.DeletionPolicySnapshot {
+		return "snapshotted"
+	}
+
+	return "retained"
+}
+
+func formatPlainRetained(retained []data.RetainedResource) string {
+	if len(retained) == 0 {
+		return ""
+	}
+
+	formatted := "The following resources were kept and may still be billed:\n"
+	for _, resource := range retained {
+		formatted += fmt.Sprintf("  %s %s %s (%s)\n", resource.LogicalResourceID, resource.ResourceType, resource.PhysicalResourceID, retainedLabel(resource.DeletionPolicy))
+	}
+
+	return formatted
+}
+
+var severityColors map[lint.Severity]string = map[lint.Severity]string{
+	lint.SeverityError:         "red",
+	lint.SeverityWarning:       "yellow",
+	lint.SeverityInformational: "grey",
+}
+
+func formatLintLocation(finding lint.Finding) string {
+	if finding.Line == 0 {
+		return strings.Join(finding.Path, "/")
+	}
+
+	return fmt.Sprintf("line %d", finding.Line)
+}
+
+func formatLintFindings(result lint.Result) string {
+	findings := result.Findings
+
+	var formatted string
+	if len(findings) == 0 {
+		formatted += "[green]No findings[white]\n\n"
+	}
+
+	groups := lint.GroupBySeverity(findings)
+
+	for _, severity := range lint.Severities {
+		group := groups[severity]
+		if len(group) == 0 {
+			continue
+		}
+
+		formatted += fmt.Sprintf("[%s::b]%s (%d)[white::-]\n\n", severityColors[severity], severity, len(group))
+
+		for _, finding := range group {
+			formatted += fmt.Sprintf("[#00b8ea]%s [grey]%s[white]\n", finding.RuleID, formatLintLocation(finding))
+			formatted += "    " + tview.Escape(finding.Message) + "\n"
+		}
+
+		formatted += "\n"
+	}
+
+	if len(result.Suppressed) > 0 {
+		formatted += fmt.Sprintf("[grey::b]Suppressed (%d)[white::-]\n\n", len(result.Suppressed))
+
+		for _, suppression := range result.Suppressed {
+			formatted += fmt.Sprintf("[grey]%s %s %s[white]\n", suppression.RuleID, suppression.Severity, formatLintLocation(suppression.Finding))
+			formatted += "    [grey]" + tview.Escape(suppression.Message) + "[white]\n"
+			formatted += "    [grey]Reason: " + tview.Escape(suppression.Reason) + "[white]\n"
+		}
+
+		formatted += "\n"
+	}
+
+	if lint.HasSeverity(findings, lint.SeverityError) {
+		formatted += "[red::b]Fix the errors before deploying[white::-]\n"
+	}
+
+	return formatted
+}
+
+func formatPlainLintFindings(result lint.Result) string {
+	findings := result.Findings
+
+	var formatted string
+	if len(findings) == 0 {
+		formatted += "No lint findings\n"
+	}
+
+	groups := lint.GroupBySeverity(findings)
+
+	for _, severity := range lint.Severities {
+		group := groups[severity]
+		if len(group) == 0 {
+			continue
+		}
+
+		formatted += fmt.Sprintf("%s (%d)\n", severity, len(group))
+
+		for _, finding := range group {
+			formatted += fmt.Sprintf("  %s %s - %s\n", finding.RuleID, formatLintLocation(finding), finding.Message)
+		}
+	}
+
+	if len(result.Suppressed) > 0 {
+		formatted += fmt.Sprintf("Suppressed (%d)\n", len(result.Suppressed))
+
+		for _, suppression := range result.Suppressed {
+			formatted += fmt.Sprintf("  %s %s %s - %s Reason: %s\n", suppression.RuleID, suppression.Severity, formatLintLocation(suppression.Finding), suppression.Message, suppression.Reason)
+		}
+	}
+
+	return formatted + "\n"
+}
+
+func formatPlainHeader(info data.StackInfo, operation cfn.StackOperation) string {
+	var formatted string
+
+	formatted += fmt.Sprintf("Stack:     %s (%s)\n", info.StackName, operation)
+	formatted += fmt.Sprintf("Id:        %s\n", info.StackID)
+	if operation != cfn.StackOperationDelete {
+		formatted += fmt.Sprintf("Changeset: %s\n", info.ChangeSetName)
+	}
+
+	return formatted + "\n"
+}
+
+func formatPlainDisplayRows(displayRows map[string]data.DisplayRow) string {
+	var formatted string
+
+	for _, row := range data.FlattenDisplayRows(displayRows, data.DisplayOptions{Sort: data.SortByLogicalID}) {
+		formatted += strings.Repeat("    ", row.Depth)
+		formatted += fmt.Sprintf("%s %-6s %s %s", cfn.ChangeSetASCII[row.Action], row.Action, row.Name(), row.ResourceType)
+
+		if row.Replacement == cloudformation.ReplacementTrue {
+			formatted += " (Replace)"
+		}
+
+		if row.Replacement == cloudformation.ReplacementConditional {
+			formatted += " (Replace conditional)"
+		}
+
+		if data.IsRetained(row.DisplayRow) {
+			formatted += " (" + string(row.DeletionPolicy) + ")"
+		}
+
+		formatted += "\n"
+	}
+
+	return formatted + "\n"
+}
+
+func typedQuestion(event engine.ChangeSetReady) string {
+	if event.Guarded != "" {
+		return fmt.Sprintf("Stack %s is guarded by rule %s. Type %s to delete it:", event.Info.StackName, event.Guarded, event.Info.StackName)
+	}
+
+	return fmt.Sprintf("Type %s or %d to execute %d destructive changes:", event.Info.StackName, len(event.Destructive), len(event.Destructive))
+}
+
+func formatPlainDestructive(destructive []data.DisplayRow) string {
+	formatted := "The following stateful resources will be removed or replaced:\n"
+
+	for _, row := range destructive {
+		formatted += fmt.Sprintf("    %s %s\n", row.LogicalResourceID, row.ResourceType)
+	}
+
+	return formatted + "\n"
+}
+
+func formatPlainEvent(event cloudformation.StackEvent) string {
+	formatted := fmt.Sprintf("%s %-45s %s %s", event.Timestamp.UTC().Format(time.RFC3339), event.ResourceStatus, *event.LogicalResourceId, *event.ResourceType)
+
+	if reason := eventReason(event); reason != "" {
+		formatted += " - " + reason
+	}
+
+	return formatted
+}
+
+func formatPlainFailures(failures []cloudformation.StackEvent) string {
+	formatted := "Operation failed. The following errors prevented the stack from deploying successfully:\n"
+
+	for _, failure := range failures {
+		formatted += fmt.Sprintf("%s - %s\n", *failure.LogicalResourceId, eventReason(failure))
+	}
+
+	return formatted
+}
+
+func formatPlainPolicyViolations(violations []policy.Violation) string {
+	formatted := fmt.Sprintf("The template breaks %d policy rules:\n", len(violations))
+	groups := policy.GroupByResource(violations)
+
+	logicalIDs := make([]string, 0, len(groups))
+	for logicalID := range groups {
+		logicalIDs = append(logicalIDs, logicalID)
+	}
+	sort.Strings(logicalIDs)
+
+	for _, logicalID := range logicalIDs {
+		group := groups[logicalID]
+		formatted += fmt.Sprintf("  %s %s (line %d)\n", logicalID, group[0].ResourceType, group[0].Line)
+
+		for _, violation := range group {
+			formatted += fmt.Sprintf("    %s - %s\n", violation.Rule, violation.Message)
+		}
+	}
+
+	return formatted + "\n"
+}
+
+var riskColors map[security.Risk]string = map[security.Risk]string{
+	security.RiskHigh:   "red",
+	security.RiskMedium: "yellow",
+}
+
+func formatSecurityCount(findings []security.Finding) string {
+	counts := security.Count(findings)
+
+	return fmt.Sprintf("%d high and %d medium risk findings", counts[security.RiskHigh], counts[security.RiskMedium])
+}
+
+func formatSecurityFindings(findings []security.Finding) string {
+	formatted := fmt.Sprintf("[white::b]Security:[white::-] %s\n", formatSecurityCount(findings))
+
+	for _, finding := range findings {
+		formatted += fmt.Sprintf("[%s::b]%-6s[white::-] [grey]%-23s[white] [white::b]%s[white::-] %s - %s\n", riskColors[finding.Risk], finding.Risk, finding.Category, finding.LogicalID, finding.ResourceType, tview.Escape(finding.Message))
+	}
+
+	return formatted
+}
+
+func formatPlainSecurityFindings(findings []security.Finding) string {
+	formatted := fmt.Sprintf("Security summary: %s\n", formatSecurityCount(findings))
+
+	for _, finding := range findings {
+		formatted += fmt.Sprintf("    %-6s %-23s %s %s - %s\n", finding.Risk, finding.Category, finding.LogicalID, finding.ResourceType, finding.Message)
+	}
+
+	return formatted + "\n"
+}
+
+//environmentSection is a titled list of effective values shown in the environment summary
+type environmentSection struct {
+	title  string
+	values []data.EffectiveValue
+}
+
+func environmentSections(environment *data.Environment) []environmentSection {
+	return []environmentSection{
+		{title: "Parameters", values: environment.Parameters},
+		{title: "Tags", values: environment.Tags},
+	}
+}
+
+//environmentKeyWidth is the width of the widest key, so values line up
+func environmentKeyWidth(environment *data.Environment) int {
+	width := 0
+
+	for _, section := range environmentSections(environment) {
+		for _, value := range section.values {
+			if len(value.Key) > width {
+				width = len(value.Key)
+			}
+		}
+	}
+
+	return width
+}
+
+func formatEnvironment(environment *data.Environment) string {
+	formatted := fmt.Sprintf("[white::b]Environment:[white::-] %s\n", tview.Escape(environment.Name))
+	width := environmentKeyWidth(environment)
+
+	for _, section := range environmentSections(environment) {
+		if len(section.values) == 0 {
+			continue
+		}
+
+		formatted += fmt.Sprintf("[grey]%s[white]\n", section.title)
+
+		for _, value := range section.values {
+			formatted += fmt.Sprintf("  [white::b]%-*s[white::-] %s [grey](%s)[white]\n", width, tview.Escape(value.Key), tview.Escape(value.Value), tview.Escape(value.Source))
+		}
+	}
+
+	return formatted
+}
+
+func formatPlainEnvironment(environment *data.Environment) string {
+	formatted := fmt.Sprintf("Environment: %s\n", environment.Name)
+	width := environmentKeyWidth(environment)
+
+	for _, section := range environmentSections(environment) {
+		if len(section.values) == 0 {
+			continue
+		}
+
+		formatted += fmt.Sprintf("  %s:\n", section.title)
+
+		for _, value := range section.values {
+			formatted += fmt.Sprintf("    %-*s %s (%s)\n", width, value.Key, value.Value, value.Source)
+		}
+	}
+
+	return formatted + "\n"
 }
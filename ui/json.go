@@ -0,0 +1,362 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/blueseph/cirrus/cfn"
+	"github.com/blueseph/cirrus/data"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/security"
+)
+
+//jsonTypeResult is the type of the final result object, which is written once per operation
+const jsonTypeResult engine.EventType = "Result"
+
+//JSONRenderer renders the stack operation lifecycle as newline delimited JSON on stdout. The first object is the change set, followed by one object
+//per event and a final result object. Since stdout is reserved for JSON, the operation is declined unless approved, and destructive changes or
+//guarded stacks are declined unless allowed
+type JSONRenderer struct {
+	Approve          bool
+	AllowDestructive bool
+
+	info      data.StackInfo
+	operation cfn.StackOperation
+}
+
+type jsonChangeDetail struct {
+	Attribute          cloudformation.ResourceAttribute  `json:"attribute"`
+	Name               string                            `json:"name,omitempty"`
+	RequiresRecreation cloudformation.RequiresRecreation `json:"requiresRecreation,omitempty"`
+	ChangeSource       cloudformation.ChangeSource       `json:"changeSource,omitempty"`
+	Evaluation         cloudformation.EvaluationType     `json:"evaluation,omitempty"`
+	CausingEntity      string                            `json:"causingEntity,omitempty"`
+}
+
+type jsonChange struct {
+	LogicalResourceID  string                             `json:"logicalResourceId"`
+	PhysicalResourceID string                             `json:"physicalResourceId,omitempty"`
+	ResourceType       string                             `json:"resourceType"`
+	Action             cloudformation.ChangeAction        `json:"action"`
+	Replacement        cloudformation.Replacement         `json:"replacement,omitempty"`
+	Scope              []cloudformation.ResourceAttribute `json:"scope,omitempty"`
+	Details            []jsonChangeDetail                 `json:"details,omitempty"`
+	Parent             string                             `json:"parent,omitempty"`
+	Destructive        bool                               `json:"destructive"`
+	DeletionPolicy     data.DeletionPolicy                `json:"deletionPolicy,omitempty"`
+}
+
+type jsonSecurityFinding struct {
+	Risk              security.Risk     `json:"risk"`
+	Category          security.Category `json:"category"`
+	LogicalResourceID string            `json:"logicalResourceId"`
+	ResourceType      string            `json:"resourceType"`
+	Message           string            `json:"message"`
+	Line              int               `json:"line,omitempty"`
+}
+
+type jsonEffectiveValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+type jsonEnvironment struct {
+	Name       string               `json:"name"`
+	Parameters []jsonEffectiveValue `json:"parameters"`
+	Tags       []jsonEffectiveValue `json:"tags"`
+}
+
+type jsonChangeSet struct {
+	Type          engine.EventType      `json:"type"`
+	StackName     string                `json:"stackName"`
+	StackID       string                `json:"stackId"`
+	ChangeSetName string                `json:"changeSetName,omitempty"`
+	Operation     cfn.StackOperation    `json:"operation"`
+	Environment   *jsonEnvironment      `json:"environment,omitempty"`
+	Security      []jsonSecurityFinding `json:"security,omitempty"`
+	Changes       []jsonChange          `json:"changes"`
+}
+
+type jsonEvent struct {
+	Type               engine.EventType              `json:"type"`
+	Timestamp          time.Time                     `json:"timestamp"`
+	LogicalResourceID  string                        `json:"logicalResourceId"`
+	PhysicalResourceID string                        `json:"physicalResourceId,omitempty"`
+	ResourceType       string                        `json:"resourceType"`
+	Status             cloudformation.ResourceStatus `json:"status"`
+	StatusReason       string                        `json:"statusReason,omitempty"`
+	Parent             string                        `json:"parent,omitempty"`
+}
+
+type jsonOutput struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	ExportName  string `json:"exportName,omitempty"`
+}
+
+type jsonRetained struct {
+	LogicalResourceID  string              `json:"logicalResourceId"`
+	PhysicalResourceID string              `json:"physicalResourceId"`
+	ResourceType       string              `json:"resourceType"`
+	DeletionPolicy     data.DeletionPolicy `json:"deletionPolicy"`
+}
+
+type jsonResult struct {
+	Type            engine.EventType   `json:"type"`
+	StackName       string             `json:"stackName"`
+	StackID         string             `json:"stackId"`
+	Operation       cfn.StackOperation `json:"operation"`
+	Status          string             `json:"status"`
+	Succeeded       bool               `json:"succeeded"`
+	DurationSeconds float64            `json:"durationSeconds"`
+	Failures        []jsonEvent        `json:"failures"`
+	Outputs         []jsonOutput       `json:"outputs"`
+	Retained        []jsonRetained     `json:"retained,omitempty"`
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func writeJSON(value interface{}) error {
+	return json.NewEncoder(os.Stdout).Encode(value)
+}
+
+func createJSONChange(change cloudformation.Change, parent string) jsonChange {
+	resourceChange := change.ResourceChange
+
+	formatted := jsonChange{
+		LogicalResourceID:  data.NestedRowKey(parent, stringValue(resourceChange.LogicalResourceId)),
+		PhysicalResourceID: stringValue(resourceChange.PhysicalResourceId),
+		ResourceType:       stringValue(resourceChange.ResourceType),
+		Action:             resourceChange.Action,
+		Replacement:        resourceChange.Replacement,
+		Scope:              resourceChange.Scope,
+		Parent:             parent,
+	}
+
+	for _, detail := range resourceChange.Details {
+		formattedDetail := jsonChangeDetail{
+			ChangeSource:  detail.ChangeSource,
+			Evaluation:    detail.Evaluation,
+			CausingEntity: stringValue(detail.CausingEntity),
+		}
+
+		if detail.Target != nil {
+			formattedDetail.Attribute = detail.Target.Attribute
+			formattedDetail.Name = stringValue(detail.Target.Name)
+			formattedDetail.RequiresRecreation = detail.Target.RequiresRecreation
+		}
+
+		formatted.Details = append(formatted.Details, formattedDetail)
+	}
+
+	return formatted
+}
+
+func markDestructive(changes []jsonChange, destructive []data.DisplayRow) []jsonChange {
+	keys := make(map[string]bool)
+	for _, row := range destructive {
+		keys[row.LogicalResourceID] = true
+	}
+
+	for i := range changes {
+		changes[i].Destructive = keys[changes[i].LogicalResourceID]
+	}
+
+	return changes
+}
+
+func createJSONChanges(event engine.ChangeSetReady) []jsonChange {
+	changes := make([]jsonChange, 0)
+
+	if event.Operation == cfn.StackOperationDelete {
+		for _, row := range data.SortDisplayRows(displayRowList(event.DisplayRows), data.SortByLogicalID) {
+			changes = append(changes, jsonChange{
+				LogicalResourceID: row.LogicalResourceID,
+				ResourceType:      row.ResourceType,
+				Action:            cloudformation.ChangeActionRemove,
+				DeletionPolicy:    row.DeletionPolicy,
+			})
+		}
+
+		return changes
+	}
+
+	for _, change := range event.Changes {
+		changes = append(changes, createJSONChange(change, ""))
+	}
+
+	parents := make([]string, 0, len(event.NestedChanges))
+	for parent := range event.NestedChanges {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+
+	for _, parent := range parents {
+		for _, change := range event.NestedChanges[parent] {
+			changes = append(changes, createJSONChange(change, parent))
+		}
+	}
+
+	return changes
+}
+
+func createJSONSecurityFindings(findings []security.Finding) []jsonSecurityFinding {
+	formatted := make([]jsonSecurityFinding, 0, len(findings))
+
+	for _, finding := range findings {
+		formatted = append(formatted, jsonSecurityFinding{
+			Risk:              finding.Risk,
+			Category:          finding.Category,
+			LogicalResourceID: finding.LogicalID,
+			ResourceType:      finding.ResourceType,
+			Message:           finding.Message,
+			Line:              finding.Line,
+		})
+	}
+
+	return formatted
+}
+
+func createJSONEffectiveValues(values []data.EffectiveValue) []jsonEffectiveValue {
+	formatted := make([]jsonEffectiveValue, 0, len(values))
+
+	for _, value := range values {
+		formatted = append(formatted, jsonEffectiveValue{Key: value.Key, Value: value.Value, Source: value.Source})
+	}
+
+	return formatted
+}
+
+func createJSONEnvironment(environment *data.Environment) *jsonEnvironment {
+	if environment == nil {
+		return nil
+	}
+
+	return &jsonEnvironment{
+		Name:       environment.Name,
+		Parameters: createJSONEffectiveValues(environment.Parameters),
+		Tags:       createJSONEffectiveValues(environment.Tags),
+	}
+}
+
+func displayRowList(displayRows map[string]data.DisplayRow) []data.DisplayRow {
+	rows := make([]data.DisplayRow, 0, len(displayRows))
+	for _, row := range displayRows {
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func createJSONEvent(eventType engine.EventType, event cloudformation.StackEvent, parent string) jsonEvent {
+	return jsonEvent{
+		Type:               eventType,
+		Timestamp:          event.Timestamp.UTC(),
+		LogicalResourceID:  stringValue(event.LogicalResourceId),
+		PhysicalResourceID: stringValue(event.PhysicalResourceId),
+		ResourceType:       stringValue(event.ResourceType),
+		Status:             event.ResourceStatus,
+		StatusReason:       eventReason(event),
+		Parent:             parent,
+	}
+}
+
+func (r *JSONRenderer) createJSONResult(result engine.StackResult, succeeded bool, failures []cloudformation.StackEvent) jsonResult {
+	formatted := jsonResult{
+		Type:            jsonTypeResult,
+		StackName:       result.Info.StackName,
+		StackID:         result.Info.StackID,
+		Operation:       r.operation,
+		Status:          string(result.Status),
+		Succeeded:       succeeded,
+		DurationSeconds: result.Duration.Seconds(),
+		Failures:        make([]jsonEvent, 0, len(failures)),
+		Outputs:         make([]jsonOutput, 0, len(result.Outputs)),
+	}
+
+	for _, failure := range failures {
+		formatted.Failures = append(formatted.Failures, createJSONEvent(engine.EventTypeResourceUpdated, failure, ""))
+	}
+
+	for _, output := range result.Outputs {
+		formatted.Outputs = append(formatted.Outputs, jsonOutput{
+			Key:         stringValue(output.OutputKey),
+			Value:       stringValue(output.OutputValue),
+			Description: stringValue(output.Description),
+			ExportName:  stringValue(output.ExportName),
+		})
+	}
+
+	for _, resource := range result.Retained {
+		formatted.Retained = append(formatted.Retained, jsonRetained{
+			LogicalResourceID:  resource.LogicalResourceID,
+			PhysicalResourceID: resource.PhysicalResourceID,
+			ResourceType:       resource.ResourceType,
+			DeletionPolicy:     resource.DeletionPolicy,
+		})
+	}
+
+	return formatted
+}
+
+//Confirm writes the change set. Without approval, it also writes a declined result, so the change set can be reviewed without executing it
+func (r *JSONRenderer) Confirm(event engine.ChangeSetReady) (bool, error) {
+	r.info = event.Info
+	r.operation = event.Operation
+
+	err := writeJSON(jsonChangeSet{
+		Type:          engine.EventTypeChangeSetReady,
+		StackName:     event.Info.StackName,
+		StackID:       event.Info.StackID,
+		ChangeSetName: event.Info.ChangeSetName,
+		Operation:     event.Operation,
+		Environment:   createJSONEnvironment(event.Environment),
+		Security:      createJSONSecurityFindings(event.Security),
+		Changes:       markDestructive(createJSONChanges(event), event.Destructive),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if !r.Approve {
+		fmt.Fprintln(os.Stderr, "Use --yes to execute the operation with JSON output")
+
+		return false, writeJSON(r.createJSONResult(engine.StackResult{Info: r.info, Status: "DECLINED"}, false, nil))
+	}
+
+	if event.RequiresTypedConfirmation() && !r.AllowDestructive {
+		fmt.Fprintln(os.Stderr, refuseDestructiveMessage)
+
+		return false, writeJSON(r.createJSONResult(engine.StackResult{Info: r.info, Status: "DECLINED"}, false, nil))
+	}
+
+	return true, nil
+}
+
+//Render writes a JSON object for every resource and stack status change, followed by the result once the stack reaches a terminal status
+func (r *JSONRenderer) Render(event engine.Event) error {
+	switch event := event.(type) {
+	case engine.ResourceUpdated:
+		return writeJSON(createJSONEvent(event.Type(), event.Event, event.Row.Parent))
+	case engine.StackUpdated:
+		return writeJSON(createJSONEvent(event.Type(), event.Event, ""))
+	case engine.StackCompleted:
+		return writeJSON(r.createJSONResult(event.StackResult, true, nil))
+	case engine.StackFailed:
+		return writeJSON(r.createJSONResult(event.StackResult, false, event.Failures))
+	}
+
+	return nil
+}
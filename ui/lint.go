@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/lint"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+var (
+	continueButtonLabel string = "Continue"
+
+	//jsonTypeLintFindings is the type of the lint findings object, written before the change set
+	jsonTypeLintFindings engine.EventType = "LintFindings"
+)
+
+type jsonLintFinding struct {
+	RuleID   string        `json:"ruleId"`
+	Severity lint.Severity `json:"severity"`
+	Message  string        `json:"message"`
+	Path     []string      `json:"path,omitempty"`
+	Line     int           `json:"line,omitempty"`
+	Column   int           `json:"column,omitempty"`
+	Source   string        `json:"source,omitempty"`
+}
+
+type jsonLintSuppression struct {
+	jsonLintFinding
+	Reason string `json:"reason"`
+}
+
+type jsonLintFindings struct {
+	Type       engine.EventType      `json:"type"`
+	Template   string                `json:"template"`
+	Findings   []jsonLintFinding     `json:"findings"`
+	Suppressed []jsonLintSuppression `json:"suppressed"`
+}
+
+//ShowLintFindings shows the lint findings grouped by severity before deploying, followed by the suppressed findings. Errors can only be quit,
+//anything less severe can be continued past. It returns whether the user continued
+func ShowLintFindings(location string, result lint.Result) (bool, error) {
+	app := tview.NewApplication()
+	blocked := lint.HasSeverity(result.Findings, lint.SeverityError)
+	confirmed := false
+
+	findingsBox := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true)
+	findingsBox.SetText(formatLintFindings(result))
+	findingsBox.SetBorder(true).SetTitle(" Lint " + location + " ")
+
+	form := tview.NewForm()
+
+	if !blocked {
+		form.AddButton(continueButtonLabel, func() {
+			confirmed = true
+			app.Stop()
+		})
+	}
+
+	form.AddButton(quitButtonLabel, app.Stop)
+	form.SetCancelFunc(func() {
+		app.SetFocus(findingsBox)
+	})
+	form.SetButtonsAlign(tview.AlignCenter).SetBorder(true).SetTitle(" Actions ")
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(findingsBox, 0, 3, true).
+		AddItem(form, 5, 0, false)
+
+	app.SetInputCapture(func(e *tcell.EventKey) *tcell.EventKey {
+		if e.Key() == tcell.KeyTab && findingsBox.HasFocus() {
+			app.SetFocus(form)
+			return nil
+		}
+
+		if e.Key() == tcell.KeyRune && e.Rune() == 'q' && findingsBox.HasFocus() {
+			app.Stop()
+			return nil
+		}
+
+		return e
+	})
+
+	err := app.SetRoot(view, true).SetFocus(form).Run()
+	if err != nil {
+		return false, err
+	}
+
+	return confirmed, nil
+}
+
+//PrintLintFindings prints the lint findings as plain text, grouped by severity and followed by the suppressed findings
+func PrintLintFindings(result lint.Result) {
+	fmt.Print(formatPlainLintFindings(result))
+}
+
+func createJSONLintFinding(finding lint.Finding) jsonLintFinding {
+	return jsonLintFinding{
+		RuleID:   finding.RuleID,
+		Severity: finding.Severity,
+		Message:  finding.Message,
+		Path:     finding.Path,
+		Line:     finding.Line,
+		Column:   finding.Column,
+		Source:   finding.Source,
+	}
+}
+
+//WriteLintFindings writes the lint findings and the suppressed findings as a JSON object
+func WriteLintFindings(location string, result lint.Result) error {
+	formatted := jsonLintFindings{
+		Type:       jsonTypeLintFindings,
+		Template:   location,
+		Findings:   make([]jsonLintFinding, 0, len(result.Findings)),
+		Suppressed: make([]jsonLintSuppression, 0, len(result.Suppressed)),
+	}
+
+	for _, finding := range result.Findings {
+		formatted.Findings = append(formatted.Findings, createJSONLintFinding(finding))
+	}
+
+	for _, suppression := range result.Suppressed {
+		formatted.Suppressed = append(formatted.Suppressed, jsonLintSuppression{
+			jsonLintFinding: createJSONLintFinding(suppression.Finding),
+			Reason:          suppression.Reason,
+		})
+	}
+
+	return writeJSON(formatted)
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/blueseph/cirrus/colors"
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/policy"
+)
+
+//jsonTypePolicyViolations is the type of the policy violations object, written before the change set
+const jsonTypePolicyViolations engine.EventType = "PolicyViolations"
+
+type jsonPolicyViolation struct {
+	Rule              string `json:"rule"`
+	Message           string `json:"message"`
+	LogicalResourceID string `json:"logicalResourceId"`
+	ResourceType      string `json:"resourceType"`
+	Line              int    `json:"line,omitempty"`
+}
+
+type jsonPolicyViolations struct {
+	Type       engine.EventType      `json:"type"`
+	Violations []jsonPolicyViolation `json:"violations"`
+}
+
+//PrintPolicyViolations prints the policy violations as plain text, grouped by resource
+func PrintPolicyViolations(violations []policy.Violation) {
+	fmt.Print(colors.Error(formatPlainPolicyViolations(violations)))
+}
+
+//WritePolicyViolations writes the policy violations as a JSON object
+func WritePolicyViolations(violations []policy.Violation) error {
+	formatted := jsonPolicyViolations{
+		Type:       jsonTypePolicyViolations,
+		Violations: make([]jsonPolicyViolation, 0, len(violations)),
+	}
+
+	for _, violation := range violations {
+		formatted.Violations = append(formatted.Violations, jsonPolicyViolation{
+			Rule:              violation.Rule,
+			Message:           violation.Message,
+			LogicalResourceID: violation.LogicalID,
+			ResourceType:      violation.ResourceType,
+			Line:              violation.Line,
+		})
+	}
+
+	return writeJSON(formatted)
+}
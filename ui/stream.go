@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/blueseph/cirrus/engine"
+	"github.com/blueseph/cirrus/utils"
+)
+
+var refuseDestructiveMessage string = "Refusing to approve destructive changes or a guarded stack. Use --allow-destructive to approve them with --yes"
+
+//StreamRenderer renders the stack operation lifecycle as plain text, one line per event, for CI logs and pipes
+type StreamRenderer struct {
+	Approve          bool
+	AllowDestructive bool
+}
+
+//Confirm prints the environment, the security summary and the changes, and asks for confirmation unless approved. Destructive changes and guarded stacks need a typed confirmation, and are
+//refused when approved without allowing destructive changes
+func (r StreamRenderer) Confirm(event engine.ChangeSetReady) (bool, error) {
+	fmt.Print(formatPlainHeader(event.Info, event.Operation))
+
+	if event.Environment != nil {
+		fmt.Print(formatPlainEnvironment(event.Environment))
+	}
+
+	if len(event.Security) > 0 {
+		fmt.Print(formatPlainSecurityFindings(event.Security))
+	}
+
+	fmt.Print(formatPlainDisplayRows(event.DisplayRows))
+
+	if len(event.Destructive) > 0 {
+		fmt.Print(formatPlainDestructive(event.Destructive))
+	}
+
+	if event.Guarded != "" {
+		fmt.Printf("Stack %s matches guard rule %s\n\n", event.Info.StackName, event.Guarded)
+	}
+
+	confirmed, err := r.confirm(event)
+	if err != nil {
+		fmt.Println("Unable to read a confirmation. Use --yes to approve without a prompt")
+		confirmed = false
+	}
+
+	if !confirmed {
+		fmt.Printf("User declined %s\n", operationSubject(event.Operation))
+	}
+
+	return confirmed, nil
+}
+
+func (r StreamRenderer) confirm(event engine.ChangeSetReady) (bool, error) {
+	typed := event.RequiresTypedConfirmation()
+
+	if r.Approve {
+		if typed && !r.AllowDestructive {
+			fmt.Println(refuseDestructiveMessage)
+			return false, nil
+		}
+
+		return true, nil
+	}
+
+	if typed {
+		answer, err := utils.AskQuestion(typedQuestion(event))
+		if err != nil {
+			return false, err
+		}
+
+		return event.ConfirmedBy(answer), nil
+	}
+
+	return utils.AskYesNoQuestion(fmt.Sprintf("Execute %s? [Y/N]", operationSubject(event.Operation)))
+}
+
+//Render prints a line for every resource and stack status change, followed by the failures once the operation fails
+func (r StreamRenderer) Render(event engine.Event) error {
+	switch event := event.(type) {
+	case engine.ResourceUpdated:
+		fmt.Println(formatPlainEvent(event.Event))
+	case engine.StackUpdated:
+		fmt.Println(formatPlainEvent(event.Event))
+	case engine.StackCompleted:
+		fmt.Println(formatPlainEvent(event.Events[len(event.Events)-1]))
+		fmt.Println("Operation succeeded")
+		fmt.Print(formatPlainRetained(event.Retained))
+	case engine.StackFailed:
+		fmt.Println(formatPlainEvent(event.Events[len(event.Events)-1]))
+		fmt.Print(formatPlainFailures(event.Failures))
+	}
+
+	return nil
+}
@@ -1,6 +1,13 @@
 package utils
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 )
 
@@ -39,3 +46,65 @@ func ReverseEvents(s []cloudformation.StackEvent) []cloudformation.StackEvent {
 
 	return a
 }
+
+// SortEvents returns a slice of events ordered from oldest to newest without side-effects
+func SortEvents(s []cloudformation.StackEvent) []cloudformation.StackEvent {
+	a := make([]cloudformation.StackEvent, len(s))
+	copy(a, s)
+
+	sort.SliceStable(a, func(i, j int) bool {
+		return a[i].Timestamp.Before(*a[j].Timestamp)
+	})
+
+	return a
+}
+
+// IsTerminal determines if the file is attached to a terminal rather than a pipe or a regular file
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// AskYesNoQuestion prints the question and reads stdin until the user answers Y or N
+func AskYesNoQuestion(question string) (bool, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println(question)
+
+	for {
+		char, _, err := reader.ReadRune()
+
+		if err != nil {
+			return false, err
+		}
+
+		char = unicode.ToLower(char)
+
+		switch char {
+		case 'y':
+			return true, nil
+		case 'n':
+			return false, nil
+		default:
+			fmt.Println("Please enter Y/N")
+		}
+	}
+}
+
+// AskQuestion prints the question and returns the next line read from stdin, without surrounding whitespace
+func AskQuestion(question string) (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println(question)
+
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+
+	return strings.TrimSpace(answer), nil
+}